@@ -1,11 +1,18 @@
 module github.com/houseabsolute/detest
 
-go 1.16
+go 1.21
 
 require (
 	github.com/jedib0t/go-pretty/v6 v6.4.6
 	github.com/mattn/go-runewidth v0.0.14
-	github.com/rivo/uniseg v0.4.4 // indirect
 	github.com/stretchr/testify v1.8.2
 	golang.org/x/sys v0.6.0
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rivo/uniseg v0.4.4 // indirect
+	github.com/stretchr/objx v0.5.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
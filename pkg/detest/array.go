@@ -0,0 +1,136 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ArrayComparer implements comparison of fixed-size array values.
+type ArrayComparer struct {
+	with func(*ArrayTester)
+}
+
+// Array takes a function which will be called to do further comparisons of
+// the array's contents.
+func (d *D) Array(with func(*ArrayTester)) ArrayComparer {
+	return ArrayComparer{with}
+}
+
+// ArrayTester is the struct that will be passed to the test function passed
+// to detest.Array. This struct implements the array-specific testing methods
+// such as Idx(), AllValues(), and Len().
+type ArrayTester struct {
+	d      *D
+	ending CollectionEnding
+	seen   map[int]bool
+}
+
+// Compare compares the array value in d.Actual() by calling the function
+// passed to `Array()`, which is in turn expected to further tests of the
+// array's content.
+func (ac ArrayComparer) Compare(d *D) {
+	v := reflect.ValueOf(d.Actual())
+	path := d.NewPath(describeTypeOfReflectValue(v), 1, "detest.(*D).Array")
+	d.PushPath(path)
+	defer d.PopPath()
+
+	v, undo, ok := d.derefActual(path, "Array()")
+	defer undo()
+	if !ok {
+		return
+	}
+
+	if !v.IsValid() || v.Kind() != reflect.Array {
+		d.AddResult(result{
+			actual: newValue(d.Actual()),
+			pass:   false,
+			where:  inDataStructure,
+			op:     "[N]",
+			description: fmt.Sprintf(
+				"Called detest.Array() but the value being tested isn't an array, it's %s",
+				articleize(describeTypeOfReflectValue(v)),
+			),
+		})
+		return
+	}
+
+	at := &ArrayTester{d: d, seen: map[int]bool{}}
+	defer at.enforceEnding()
+	ac.with(at)
+}
+
+// Len checks that the array has exactly the given number of elements.
+func (at *ArrayTester) Len(n int) {
+	at.d.PushPath(at.d.NewPath("len", 0, ""))
+	defer at.d.PopPath()
+
+	l := reflect.ValueOf(at.d.Actual()).Len()
+	result := result{
+		actual: newValue(l),
+		expect: newValue(n),
+		op:     "len ==",
+		pass:   l == n,
+	}
+	if !result.pass {
+		result.where = inDataStructure
+		result.description = fmt.Sprintf("The array has %d elements but you expected %d", l, n)
+	}
+	at.d.AddResult(result)
+}
+
+// Idx takes an array index and an expected value for that index. If the index
+// is past the end of the array, this is considered a failure.
+func (at *ArrayTester) Idx(idx int, expect interface{}) {
+	at.d.PushPath(at.d.NewPath(fmt.Sprintf("[%d]", idx), 0, ""))
+	defer at.d.PopPath()
+
+	elem, ok := elemAt(at.d, "array", idx)
+	if !ok {
+		return
+	}
+
+	at.d.PushActual(elem)
+	defer at.d.PopActual()
+
+	at.seen[idx] = true
+
+	if c, ok := expect.(Comparer); ok {
+		c.Compare(at.d)
+	} else {
+		at.d.Equal(expect).Compare(at.d)
+	}
+}
+
+// AllValues takes a function and turns it into a `FuncComparer`. It then
+// passes every array value to that comparer in turn. The function must take
+// exactly one value matching the array values' type and return a single
+// boolean value.
+func (at *ArrayTester) AllValues(check interface{}) {
+	at.d.PushPath(at.d.NewPath("range", 0, ""))
+	defer at.d.PopPath()
+
+	comparer, ok := allValuesComparer(at.d, check)
+	if !ok {
+		return
+	}
+
+	array := reflect.ValueOf(at.d.Actual())
+	for i := 0; i < array.Len(); i++ {
+		at.Idx(i, comparer)
+	}
+}
+
+// Etc means that not all elements of the array will be tested.
+func (at *ArrayTester) Etc() {
+	at.ending = Etc
+}
+
+// End means that all elements of the array must be tested or else the test
+// will fail.
+func (at *ArrayTester) End() {
+	at.ending = End
+}
+
+func (at *ArrayTester) enforceEnding() {
+	enforceIdxEnding(at.d, "Array()", "array", at.ending, at.seen)
+}
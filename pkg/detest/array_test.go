@@ -0,0 +1,302 @@
+package detest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArray(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"Passing test", arrayPassingTest},
+		{"Failing test", arrayFailingTest},
+		{"Passed slice to Array", arrayPassedSlice},
+		{"Idx called past end of array", arrayIdxCalledPastEnd},
+		{"Len fails", arrayLenFails},
+		{"AllValues fail", arrayFailWithAllValues},
+		{"AllValues not given a func", arrayPassNonFuncToAllValues},
+		{"No call to Etc or End", arrayNoCallToEtcOrEnd},
+		{"Calls End but does not check all values", arrayCallsEndButDoesNotCheckAllValues},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+func arrayPassingTest(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		[3]float64{1, 2, 3},
+		d.Array(func(at *ArrayTester) {
+			at.Len(3)
+			at.Idx(0, 1.0)
+			at.Idx(1, d.GT(1.5))
+			at.Idx(2, 3.0)
+			at.End()
+		}),
+		"array",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: array\n")
+}
+
+func arrayFailingTest(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[4]byte{1, 2, 3, 4},
+		r.Array(func(at *ArrayTester) {
+			at.Idx(0, byte(1))
+			at.Idx(3, byte(5))
+			at.Etc()
+		}),
+		"array[3] == 5",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 1, "one state was recorded")
+	assert.Len(t, r.record[0].output, 2, "record has state with two output items")
+	assert.Equal(
+		t,
+		&result{
+			actual: &value{value: byte(4), desc: "uint8"},
+			expect: &value{value: byte(5), desc: "uint8"},
+			op:     "==",
+			pass:   false,
+			path: []Path{
+				{
+					data:   "[4]uint8",
+					callee: "detest.(*D).Array",
+					caller: "detest.(*DetestRecorder).Is",
+				},
+				{
+					data:   "[3]",
+					callee: "detest.(*ArrayTester).Idx",
+					caller: "detest.arrayFailingTest.func1",
+				},
+				{
+					data:   "uint8",
+					callee: "detest.(*D).Equal",
+					caller: "detest.arrayFailingTest.func1",
+				},
+			},
+			where:       inValue,
+			description: "",
+		},
+		r.record[0].output[1].result,
+		"got the expected result",
+	)
+}
+
+func arrayPassedSlice(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]int{1},
+		r.Array(func(at *ArrayTester) {
+			at.Idx(0, 1)
+			at.End()
+		}),
+		"slice",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[]int"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Called detest.Array() but the value being tested isn't an array, it's a []int",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func arrayIdxCalledPastEnd(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[2]int{1, 2},
+		r.Array(func(at *ArrayTester) {
+			at.Idx(2, 3)
+			at.End()
+		}),
+		"past end",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[2]int", "[2]"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Attempted to get an index (2) past the end of a 2-element array",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func arrayLenFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[2]int{1, 2},
+		r.Array(func(at *ArrayTester) {
+			at.Len(3)
+			at.Etc()
+		}),
+		"len",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[2]int", "len"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"The array has 2 elements but you expected 3",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func arrayFailWithAllValues(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[3]int{1, 2, 3},
+		r.Array(func(at *ArrayTester) {
+			at.AllValues(func(v int) bool { return v < 3 })
+			at.End()
+		}),
+		"all values",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     true,
+				dataPath: []string{"[3]int", "range", "[0]", "int"},
+			},
+			{
+				pass:     true,
+				dataPath: []string{"[3]int", "range", "[1]", "int"},
+			},
+			{
+				pass:     false,
+				dataPath: []string{"[3]int", "range", "[2]", "int"},
+			},
+		},
+		"got expected results",
+	)
+}
+
+func arrayPassNonFuncToAllValues(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[1]int{1},
+		r.Array(func(at *ArrayTester) {
+			at.AllValues(42)
+			at.End()
+		}),
+		"non-func",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[1]int", "range"},
+			},
+		},
+		"got expected results",
+	)
+}
+
+func arrayNoCallToEtcOrEnd(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[1]int{1},
+		r.Array(func(at *ArrayTester) {
+			at.Idx(0, 1)
+		}),
+		"no Etc or End",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 2, "record has state with two output items")
+	assert.Equal(
+		t,
+		"The function passed to Array() did not call Etc() or End()",
+		r.record[0].output[1].warning,
+		"got the expected warning",
+	)
+}
+
+func arrayCallsEndButDoesNotCheckAllValues(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[3]int{1, 2, 3},
+		r.Array(func(at *ArrayTester) {
+			at.Idx(1, 2)
+			at.End()
+		}),
+		"End",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"[3]int", "[1]", "int"}},
+			{pass: false, dataPath: []string{"[3]int"}},
+			{pass: false, dataPath: []string{"[3]int"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Your array test did not check index 2",
+		r.record[0].output[2].result.description,
+		"got expected description",
+	)
+}
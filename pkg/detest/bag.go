@@ -0,0 +1,296 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// BagComparer implements comparison of slice and array values without regard
+// to the order of their elements.
+type BagComparer struct {
+	with func(*BagTester)
+	set  bool
+}
+
+// Bag takes a function which will be called to say what elements a slice or
+// array should contain, in any order. Each expectation passed to
+// `BagTester.Item` must match its own element, so if you expect the same
+// value twice then the slice must contain it at least twice.
+func (d *D) Bag(with func(*BagTester)) BagComparer {
+	return BagComparer{with: with}
+}
+
+// Set is like Bag, but it ignores duplicates. Each expectation passed to
+// `BagTester.Item` only needs to match some element, and several
+// expectations can match the same element. When `End()` is called, each
+// element only needs to match some expectation.
+func (d *D) Set(with func(*BagTester)) BagComparer {
+	return BagComparer{with: with, set: true}
+}
+
+// BagTester is the struct that will be passed to the test function passed to
+// detest.Bag or detest.Set. The expectations it records are not checked until
+// that function returns, since every element may need to be compared to every
+// expectation.
+type BagTester struct {
+	d       *D
+	ending  CollectionEnding
+	endPath Path
+	items   []bagItem
+}
+
+type bagItem struct {
+	expect interface{}
+	path   Path
+}
+
+// Item takes an expected value for one of the elements. The expected value
+// can be a literal value or a `Comparer`. If no element matches it, this is
+// considered a failure.
+func (bt *BagTester) Item(expect interface{}) {
+	bt.items = append(bt.items, bagItem{
+		expect: expect,
+		path:   bt.d.NewPath("[?]", 0, ""),
+	})
+}
+
+// Etc means that the slice may contain elements which do not match any
+// expectation.
+func (bt *BagTester) Etc() {
+	bt.ending = Etc
+}
+
+// End means that every element must match an expectation or else the test
+// will fail.
+func (bt *BagTester) End() {
+	bt.ending = End
+	bt.endPath = bt.d.NewPath("", 0, "")
+}
+
+// Compare compares the slice or array value in d.Actual() by calling the
+// function passed to `Bag()` or `Set()` and then matching the expectations it
+// recorded against the elements.
+func (bc BagComparer) Compare(d *D) {
+	called := "Bag"
+	if bc.set {
+		called = "Set"
+	}
+
+	v := reflect.ValueOf(d.Actual())
+	path := d.NewPath(describeTypeOfReflectValue(v), 1, "detest.(*D)."+called)
+	d.PushPath(path)
+	defer d.PopPath()
+
+	v, undo, ok := d.derefActual(path, called+"()")
+	defer undo()
+	if !ok {
+		return
+	}
+
+	if !v.IsValid() || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		d.AddResult(result{
+			actual: newValue(d.Actual()),
+			pass:   false,
+			where:  inDataStructure,
+			op:     "[?]",
+			description: fmt.Sprintf(
+				"Called detest.%s() but the value being tested isn't a slice or array, it's %s",
+				called,
+				articleize(describeTypeOfReflectValue(v)),
+			),
+		})
+		return
+	}
+
+	bt := &BagTester{d: d}
+	bc.with(bt)
+
+	// We compare every expectation to every element up front. The output of
+	// each comparison is saved so that we can add the output for the
+	// element each expectation ends up matching. If comparing an
+	// expectation produces a usage error, we save that output instead, since
+	// the error will be the same for every element.
+	passes := make([][]bool, len(bt.items))
+	outputs := make([][][]outputItem, len(bt.items))
+	usageErrors := make([][]outputItem, len(bt.items))
+	for i, item := range bt.items {
+		c, ok := item.expect.(Comparer)
+		if !ok {
+			c = d.Equal(item.expect)
+		}
+
+		passes[i] = make([]bool, v.Len())
+		outputs[i] = make([][]outputItem, v.Len())
+		for j := 0; j < v.Len(); j++ {
+			d.PushPath(elemPath(item.path, j))
+			d.PushActual(v.Index(j).Interface())
+			passes[i][j], outputs[i][j] = d.evaluate(c)
+			d.PopActual()
+			d.PopPath()
+			if usageErrors[i] == nil && hasUsageError(outputs[i][j]) {
+				usageErrors[i] = outputs[i][j]
+			}
+		}
+	}
+
+	var itemMatches []int
+	var elemMatched []bool
+	if bc.set {
+		itemMatches, elemMatched = matchSet(passes, v.Len())
+	} else {
+		itemMatches, elemMatched = matchBag(passes, v.Len())
+	}
+
+	for i, item := range bt.items {
+		if j := itemMatches[i]; j >= 0 {
+			d.state.output = append(d.state.output, outputs[i][j]...)
+			continue
+		}
+		if usageErrors[i] != nil {
+			d.state.output = append(d.state.output, usageErrors[i]...)
+			continue
+		}
+
+		r := result{
+			pass:  false,
+			where: inValue,
+			op:    "[?]",
+			description: fmt.Sprintf(
+				"None of the %d element(s) matched this expectation", v.Len()),
+		}
+		if _, ok := item.expect.(Comparer); !ok {
+			r.expect = newValue(item.expect)
+		}
+		if !bc.set && matchesAny(passes[i]) {
+			r.description = "Every element that matched this expectation was already matched by another expectation"
+		}
+		d.PushPath(item.path)
+		d.AddResult(r)
+		d.PopPath()
+	}
+
+	bt.enforceEnding(called, elemMatched)
+}
+
+// elemPath returns the path for the element at the given index. It uses the
+// callee and caller from the given path, which is created in one of the
+// BagTester's methods, so that the caller is the user's test function.
+func elemPath(p Path, idx int) Path {
+	p.data = fmt.Sprintf("[%d]", idx)
+	return p
+}
+
+func (bt *BagTester) enforceEnding(called string, elemMatched []bool) {
+	d := bt.d
+
+	// If we got an error in anything but a value check that means the test
+	// aborted. This could mean an expectation with a usage error.
+	if d.lastResultIsNonValueError() {
+		return
+	}
+
+	if bt.ending == Etc {
+		return
+	}
+
+	if bt.ending == Unset {
+		d.AddWarning(fmt.Sprintf("The function passed to %s() did not call Etc() or End()", called))
+		return
+	}
+
+	v := reflect.ValueOf(d.Actual())
+	for j, matched := range elemMatched {
+		if matched {
+			continue
+		}
+		d.PushPath(elemPath(bt.endPath, j))
+		d.AddResult(result{
+			actual:      newValue(v.Index(j).Interface()),
+			pass:        false,
+			where:       inValue,
+			description: fmt.Sprintf("The element at index %d did not match any expectation", j),
+		})
+		d.PopPath()
+	}
+}
+
+// matchBag finds an assignment of expectations to elements where each
+// expectation gets its own element, matching as many expectations as
+// possible. It returns the element index matched by each expectation, or -1
+// if it was not matched, and whether each element was matched.
+//
+// This is a maximum bipartite matching, found with the augmenting path
+// algorithm. The greedy approach of giving each expectation the first
+// element it matches would fail for something like
+// `bt.Item(d.HasPrefix("a"))` followed by `bt.Item("ab")` when the slice is
+// `["ab", "ac"]`.
+func matchBag(passes [][]bool, elems int) ([]int, []bool) {
+	elemMatches := make([]int, elems)
+	for j := range elemMatches {
+		elemMatches[j] = -1
+	}
+
+	var augment func(i int, visited []bool) bool
+	augment = func(i int, visited []bool) bool {
+		for j := 0; j < elems; j++ {
+			if !passes[i][j] || visited[j] {
+				continue
+			}
+			visited[j] = true
+			if elemMatches[j] < 0 || augment(elemMatches[j], visited) {
+				elemMatches[j] = i
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := range passes {
+		augment(i, make([]bool, elems))
+	}
+
+	itemMatches := make([]int, len(passes))
+	for i := range itemMatches {
+		itemMatches[i] = -1
+	}
+	elemMatched := make([]bool, elems)
+	for j, i := range elemMatches {
+		if i >= 0 {
+			itemMatches[i] = j
+			elemMatched[j] = true
+		}
+	}
+
+	return itemMatches, elemMatched
+}
+
+// matchSet is like matchBag, but any number of expectations can match the
+// same element. Each expectation is matched to the first element it matches,
+// and an element is matched if any expectation matches it.
+func matchSet(passes [][]bool, elems int) ([]int, []bool) {
+	itemMatches := make([]int, len(passes))
+	elemMatched := make([]bool, elems)
+	for i := range passes {
+		itemMatches[i] = -1
+		for j := 0; j < elems; j++ {
+			if !passes[i][j] {
+				continue
+			}
+			if itemMatches[i] < 0 {
+				itemMatches[i] = j
+			}
+			elemMatched[j] = true
+		}
+	}
+
+	return itemMatches, elemMatched
+}
+
+func matchesAny(passes []bool) bool {
+	for _, p := range passes {
+		if p {
+			return true
+		}
+	}
+	return false
+}
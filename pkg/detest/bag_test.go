@@ -0,0 +1,337 @@
+package detest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBag(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"Passing test", bagPassingTest},
+		{"Needs an assignment of expectations to elements", bagNeedsAssignment},
+		{"Unmatched expectation", bagUnmatchedExpectation},
+		{"Expectation whose elements were all matched by others", bagExpectationAlreadyMatched},
+		{"Calls End with unmatched elements", bagCallsEndWithUnmatchedElements},
+		{"Calls Etc with unmatched elements", bagCallsEtcWithUnmatchedElements},
+		{"No call to Etc or End", bagNoCallToEtcOrEnd},
+		{"Expectation with a usage error", bagExpectationWithUsageError},
+		{"Passed non-slice to Bag", bagPassedNonSlice},
+		{"Array", bagArray},
+		{"Set ignores duplicates", bagSetIgnoresDuplicates},
+		{"Set with unmatched elements", bagSetWithUnmatchedElements},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+func bagPassingTest(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		[]string{"c", "a", "b"},
+		d.Bag(func(bt *BagTester) {
+			bt.Item("a")
+			bt.Item("b")
+			bt.Item(d.HasPrefix("c"))
+			bt.End()
+		}),
+		"bag of a, b, c",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: bag of a, b, c\n")
+}
+
+func bagNeedsAssignment(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]string{"ab", "ac"},
+		r.Bag(func(bt *BagTester) {
+			bt.Item(r.HasPrefix("a"))
+			bt.Item("ab")
+			bt.End()
+		}),
+		"prefix and exact",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"[]string", "[1]", "string"}},
+			{pass: true, dataPath: []string{"[]string", "[0]", "string"}},
+		},
+		"each expectation got its own element",
+	)
+	assert.Equal(
+		t,
+		Path{
+			data:   "[1]",
+			callee: "detest.(*BagTester).Item",
+			caller: "detest.bagNeedsAssignment.func1",
+		},
+		r.record[0].output[0].result.path[1],
+		"element path has the test function as its caller",
+	)
+}
+
+func bagUnmatchedExpectation(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]int{1, 2},
+		r.Bag(func(bt *BagTester) {
+			bt.Item(2)
+			bt.Item(3)
+			bt.Etc()
+		}),
+		"unmatched expectation",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"[]int", "[1]", "int"}},
+			{pass: false, dataPath: []string{"[]int", "[?]"}},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[1].result
+	assert.Equal(t, inValue, res.where, "failure is in the value")
+	assert.Equal(t, &value{value: 3, desc: "int"}, res.expect, "expect is the literal value")
+	assert.Equal(t, "None of the 2 element(s) matched this expectation", res.description, "got expected description")
+	assert.Equal(
+		t,
+		"detest.bagUnmatchedExpectation.func1",
+		res.path[1].caller,
+		"unmatched expectation path has the test function as its caller",
+	)
+}
+
+func bagExpectationAlreadyMatched(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]int{1, 2},
+		r.Bag(func(bt *BagTester) {
+			bt.Item(1)
+			bt.Item(1)
+			bt.Etc()
+		}),
+		"duplicate expectation",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 2, "record has state with two output items")
+	assert.Equal(
+		t,
+		"Every element that matched this expectation was already matched by another expectation",
+		r.record[0].output[1].result.description,
+		"got expected description",
+	)
+}
+
+func bagCallsEndWithUnmatchedElements(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]int{1, 2, 3, 4},
+		r.Bag(func(bt *BagTester) {
+			bt.Item(3)
+			bt.Item(5)
+			bt.End()
+		}),
+		"unmatched elements",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"[]int", "[2]", "int"}},
+			{pass: false, dataPath: []string{"[]int", "[?]"}},
+			{pass: false, dataPath: []string{"[]int", "[0]"}},
+			{pass: false, dataPath: []string{"[]int", "[1]"}},
+			{pass: false, dataPath: []string{"[]int", "[3]"}},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[4].result
+	assert.Equal(t, &value{value: 4, desc: "int"}, res.actual, "actual is the unmatched element")
+	assert.Equal(
+		t,
+		"The element at index 3 did not match any expectation",
+		res.description,
+		"got expected description",
+	)
+	assert.Equal(
+		t,
+		Path{
+			data:   "[3]",
+			callee: "detest.(*BagTester).End",
+			caller: "detest.bagCallsEndWithUnmatchedElements.func1",
+		},
+		res.path[1],
+		"unmatched element path has the test function as its caller",
+	)
+}
+
+func bagCallsEtcWithUnmatchedElements(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		[]int{1, 2, 3},
+		d.Bag(func(bt *BagTester) {
+			bt.Item(3)
+			bt.Etc()
+		}),
+		"Etc",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: Etc\n")
+}
+
+func bagNoCallToEtcOrEnd(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]int{1},
+		r.Bag(func(bt *BagTester) {
+			bt.Item(1)
+		}),
+		"no Etc or End",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 2, "record has state with two output items")
+	assert.Equal(
+		t,
+		"The function passed to Bag() did not call Etc() or End()",
+		r.record[0].output[1].warning,
+		"got the expected warning",
+	)
+}
+
+func bagExpectationWithUsageError(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]int{1, 2},
+		r.Bag(func(bt *BagTester) {
+			bt.Item(r.Between(3, 1))
+			bt.End()
+		}),
+		"usage error",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"[]int", "[0]", "int"}},
+		},
+		"only the usage error is reported",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, inUsage, res.where, "failure is a usage error")
+	assert.Equal(
+		t,
+		"The low value passed to detest.(*D).Between() (3) is greater than the high value (1)",
+		res.description,
+		"got the expectation's description",
+	)
+}
+
+func bagPassedNonSlice(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[int]int{1: 1},
+		r.Set(func(bt *BagTester) {
+			bt.Item(1)
+			bt.End()
+		}),
+		"non-slice",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inDataStructure, res.where, "failure is in the data structure")
+	assert.Equal(t, "detest.(*D).Set", res.path[0].callee, "callee is Set")
+	assert.Equal(
+		t,
+		"Called detest.Set() but the value being tested isn't a slice or array, it's a map[int]int",
+		res.description,
+		"got expected description",
+	)
+}
+
+func bagArray(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		[3]int{3, 1, 2},
+		d.Bag(func(bt *BagTester) {
+			bt.Item(1)
+			bt.Item(2)
+			bt.Item(3)
+			bt.End()
+		}),
+		"array",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: array\n")
+}
+
+func bagSetIgnoresDuplicates(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		[]string{"b", "a", "b", "a"},
+		d.Set(func(bt *BagTester) {
+			bt.Item("a")
+			bt.Item("a")
+			bt.Item("b")
+			bt.End()
+		}),
+		"set of a, b",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: set of a, b\n")
+}
+
+func bagSetWithUnmatchedElements(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]string{"a", "c", "a"},
+		r.Set(func(bt *BagTester) {
+			bt.Item("a")
+			bt.End()
+		}),
+		"set with unmatched element",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"[]string", "[0]", "string"}},
+			{pass: false, dataPath: []string{"[]string", "[1]"}},
+		},
+		"got expected results",
+	)
+}
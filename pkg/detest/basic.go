@@ -1,7 +1,6 @@
 package detest
 
 import (
-	"bytes"
 	"fmt"
 	"math"
 	"reflect"
@@ -13,6 +12,7 @@ import (
 // that they're equal.
 type ExactEqualityComparer struct {
 	expect interface{}
+	opts   equalOptions
 }
 
 // Is tests that two variables are exactly equal. The first variable is the
@@ -93,9 +93,13 @@ func (d *D) Require(ok bool, args ...interface{}) {
 }
 
 // Equal takes an expected literal value and returns an ExactEqualityComparer
-// for later use.
-func (d *D) Equal(expect interface{}) ExactEqualityComparer {
-	return ExactEqualityComparer{expect}
+// for later use. It also accepts any number of options, like
+// `IgnoreFields("ID")`, which change how the values are compared.
+//
+// Struct fields with a `detest:"-"` or `detest:"ignore"` tag are always
+// skipped.
+func (d *D) Equal(expect interface{}, opts ...EqualOption) ExactEqualityComparer {
+	return ExactEqualityComparer{expect, newEqualOptions(opts)}
 }
 
 // Compare compares the value in d.Actual() to the expected value passed to
@@ -103,7 +107,8 @@ func (d *D) Equal(expect interface{}) ExactEqualityComparer {
 func (eec ExactEqualityComparer) Compare(d *D) {
 	actual := d.Actual()
 	actualType := reflect.TypeOf(actual)
-	d.PushPath(d.NewPath(describeType(actualType), 1, "detest.(*D).Equal"))
+	path := d.NewPath(describeType(actualType), 1, "detest.(*D).Equal")
+	d.PushPath(path)
 	defer d.PopPath()
 
 	expect := eec.expect
@@ -115,15 +120,20 @@ func (eec ExactEqualityComparer) Compare(d *D) {
 
 	expectType := reflect.TypeOf(expect)
 	if actualType == expectType {
-		result.pass = exactCompare(actual, expect)
-		if !result.pass {
-			result.where = inValue
-		}
-	} else {
-		result.pass = nilValuesAreEqual(actual, expect)
-		if result.pass {
-			result.where = inType
+		// The deep comparison records a failed result for each element that
+		// differs, so we only need to add a result here if it passes.
+		de := newDeepEqualer(d, path, result.op, true)
+		de.opts = eec.opts
+		if de.run(actual, expect) {
+			result.pass = true
+			d.AddResult(result)
 		}
+		return
+	}
+
+	result.pass = nilValuesAreEqual(actual, expect)
+	if result.pass {
+		result.where = inType
 	}
 
 	d.AddResult(result)
@@ -146,7 +156,8 @@ func (d *D) NotEqual(expect interface{}) ExactInequalityComparer {
 func (eic ExactInequalityComparer) Compare(d *D) {
 	actual := d.Actual()
 	actualType := reflect.TypeOf(actual)
-	d.PushPath(d.NewPath(describeType(actualType), 1, "detest.(*D).NotEqual"))
+	path := d.NewPath(describeType(actualType), 1, "detest.(*D).NotEqual")
+	d.PushPath(path)
 	defer d.PopPath()
 
 	expect := eic.expect
@@ -158,7 +169,8 @@ func (eic ExactInequalityComparer) Compare(d *D) {
 
 	expectType := reflect.TypeOf(expect)
 	if actualType == expectType {
-		result.pass = !exactCompare(actual, expect)
+		de := newDeepEqualer(d, path, result.op, false)
+		result.pass = !de.compare(reflect.ValueOf(actual), reflect.ValueOf(expect))
 		if !result.pass {
 			result.where = inValue
 		}
@@ -204,30 +216,10 @@ func isNilable(kind reflect.Kind) bool {
 		kind == reflect.UnsafePointer
 }
 
-func exactCompare(actual, expect interface{}) bool {
-	if actual == nil || expect == nil {
-		// Two nils are only equal if they're also the same type.
-		return actual == expect
-	}
-
-	exp, ok := expect.([]byte)
-	if !ok {
-		// Need to replace this with something that traverses a data structure
-		// recording our path as we go.
-		return reflect.DeepEqual(actual, expect)
-	}
-
-	act, ok := actual.([]byte)
-	if !ok {
-		return false
-	}
-
-	return bytes.Equal(act, exp)
-}
-
 // ValueEqualityComparer implements value-based comparison of two values.
 type ValueEqualityComparer struct {
 	expect interface{}
+	opts   equalOptions
 }
 
 // ValueIs tests that two variables contain the same value. The first variable
@@ -237,7 +229,9 @@ type ValueEqualityComparer struct {
 //
 // If the two variables to be compared are of different types this is fine as
 // long as one type can be converted to the other (for example `int32` and
-// `int64`).
+// `int64`). This applies to every value inside slices, arrays, maps, structs,
+// and pointers as well, so a `map[string]interface{}` containing a `float64`
+// can be compared to a `map[string]int`.
 //
 // Under the hood this is implemented with the ValueEqualityComparer.
 func (d *D) ValueIs(actual, expect interface{}, args ...interface{}) bool {
@@ -250,9 +244,10 @@ func (d *D) ValueIs(actual, expect interface{}, args ...interface{}) bool {
 }
 
 // ValueEqual takes an expected literal value and returns a
-// ValueEqualityComparer for later use.
-func (d *D) ValueEqual(expect interface{}) ValueEqualityComparer {
-	return ValueEqualityComparer{expect}
+// ValueEqualityComparer for later use. It accepts the same options as
+// `d.Equal`.
+func (d *D) ValueEqual(expect interface{}, opts ...EqualOption) ValueEqualityComparer {
+	return ValueEqualityComparer{expect, newEqualOptions(opts)}
 }
 
 // Compare compares the value in d.Actual() to the expected value passed to
@@ -260,7 +255,8 @@ func (d *D) ValueEqual(expect interface{}) ValueEqualityComparer {
 func (vec ValueEqualityComparer) Compare(d *D) {
 	actual := d.Actual()
 	actualType := reflect.TypeOf(actual)
-	d.PushPath(d.NewPath(describeType(actualType), 1, "detest.(*D).ValueEqual"))
+	path := d.NewPath(describeType(actualType), 1, "detest.(*D).ValueEqual")
+	d.PushPath(path)
 	defer d.PopPath()
 
 	expect := vec.expect
@@ -270,45 +266,16 @@ func (vec ValueEqualityComparer) Compare(d *D) {
 		op:     "== (value)",
 	}
 
-	expectType := reflect.TypeOf(expect)
-	if actualType == expectType {
-		result.pass = exactCompare(actual, expect)
-		if !result.pass {
-			result.where = inValue
-		}
-		d.AddResult(result)
-		return
-	}
-
-	if nilValuesAreEqual(actual, expect) || actual == nil && expect == nil {
+	// The deep comparison converts between types as it goes, so numbers and
+	// types with the same underlying type are compared by value all the way
+	// down the data structure.
+	de := newDeepEqualer(d, path, result.op, true)
+	de.byValue = true
+	de.opts = vec.opts
+	if de.run(actual, expect) {
 		result.pass = true
 		d.AddResult(result)
-		return
-	}
-
-	if !actualType.ConvertibleTo(expectType) {
-		result.pass = false
-		result.where = inType
-		result.description = cannotConvertMessage(actualType, expectType)
-		d.AddResult(result)
-		return
-	}
-
-	actualVal, expectVal, desc := maybeConvertValues(actual, expect, actualType, expectType)
-	if desc != "" {
-		result.pass = false
-		result.where = inType
-		result.description = desc
-		d.AddResult(result)
-		return
 	}
-
-	result.pass = actualVal.Interface() == expectVal.Interface()
-	if !result.pass {
-		result.where = inValue
-	}
-
-	d.AddResult(result)
 }
 
 func cannotConvertMessage(actualType, expectType reflect.Type) string {
@@ -319,29 +286,6 @@ func cannotConvertMessage(actualType, expectType reflect.Type) string {
 	)
 }
 
-func maybeConvertValues(actual, expect interface{}, actualType, expectType reflect.Type) (
-	reflect.Value, reflect.Value, string,
-) {
-	actualVal := reflect.ValueOf(actual)
-	expectVal := reflect.ValueOf(expect)
-
-	if actualNumeric := isNumeric(actualVal); actualNumeric != nil {
-		if expectNumeric := isNumeric(expectVal); expectNumeric != nil {
-			return safelyConvertNumberTypes(actualVal, expectVal, actualNumeric, expectNumeric)
-		}
-
-		// It shouldn't really be possible to get here since we checked the
-		// results of ConvertibleTo for the 2 types earlier.
-		return actualVal, expectVal, cannotConvertMessage(actualType, expectType)
-	}
-
-	// We should only end up here when we have two non-numeric types that are
-	// identical in form but not name. This can happen when one type is an
-	// alias for the other (`type StringLike string`) or when two structs have
-	// identical fields but different type names.
-	return actualVal, expectVal.Convert(actualType), ""
-}
-
 type numericInfo struct {
 	baseType string
 	bits     int
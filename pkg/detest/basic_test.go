@@ -245,6 +245,8 @@ func TestValueIs(t *testing.T) {
 	t.Run("Complex comparisons", testComplexComparisons)
 	t.Run("String comparisons", testStringComparisons)
 	t.Run("Struct comparisons", testStructComparisons)
+	t.Run("Nested comparisons", testNestedValueComparisons)
+	t.Run("Nested comparison failures", testNestedValueComparisonFailures)
 	t.Run("Can handle nil", func(t *testing.T) {
 		mT := new(mockT)
 		d := NewWithOutput(mT, mT)
@@ -460,6 +462,135 @@ func testStructComparisons(t *testing.T) {
 	}
 }
 
+func testNestedValueComparisons(t *testing.T) {
+	type stringish string
+
+	tests := []struct {
+		name   string
+		actual interface{}
+		expect interface{}
+	}{
+		{
+			"map of interface and map of int",
+			map[string]interface{}{"n": float64(3)},
+			map[string]int{"n": 3},
+		},
+		{
+			"slice of maps from JSON",
+			[]interface{}{map[string]interface{}{"a": float64(1), "b": "x"}},
+			[]map[string]interface{}{{"a": 1, "b": "x"}},
+		},
+		{
+			"map keys of different types",
+			map[stringish]int8{"a": 1},
+			map[string]uint64{"a": 1},
+		},
+		{
+			"arrays of different types",
+			[2]int32{1, 2},
+			[2]float64{1, 2},
+		},
+		{
+			"pointers to different types",
+			&[]int{1},
+			&[]uint{1},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mT := new(mockT)
+			d := NewWithOutput(mT, mT)
+			d.ValueIs(test.actual, test.expect, test.name)
+			mT.AssertNotCalled(t, "Fail")
+			mT.AssertCalled(t, "WriteString", fmt.Sprintf("Assertion ok: %s\n", test.name))
+		})
+	}
+}
+
+func testNestedValueComparisonFailures(t *testing.T) {
+	t.Run("value differs", func(t *testing.T) {
+		mT := new(mockT)
+		d := NewWithOutput(mT, mT)
+		r := NewRecorder(d)
+		r.ValueIs(
+			map[string]interface{}{"a": []interface{}{float64(1), float64(2)}},
+			map[string][]int{"a": {1, 3}},
+		)
+		mT.AssertCalled(t, "Fail")
+		AssertResultsAre(
+			t,
+			r.record[0].output,
+			[]resultExpect{
+				{
+					pass:     false,
+					dataPath: []string{"map[string]interface {}", "[a]", "[1]"},
+				},
+			},
+			"got expected results",
+		)
+		assert.Equal(t, inValue, r.record[0].output[0].result.where, "failure is in the value")
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		mT := new(mockT)
+		d := NewWithOutput(mT, mT)
+		r := NewRecorder(d)
+		r.ValueIs(
+			map[string]uint8{"n": math.MaxUint8},
+			map[string]int8{"n": 0},
+		)
+		mT.AssertCalled(t, "Fail")
+		AssertResultsAre(
+			t,
+			r.record[0].output,
+			[]resultExpect{
+				{
+					pass:     false,
+					dataPath: []string{"map[string]uint8", "[n]"},
+				},
+			},
+			"got expected results",
+		)
+		assert.Equal(t, inType, r.record[0].output[0].result.where, "failure is in the type")
+		assert.Equal(
+			t,
+			"Cannot convert 8-bit uint (255) to 8-bit int without overflow",
+			r.record[0].output[0].result.description,
+			"got expected description",
+		)
+	})
+
+	t.Run("cannot convert", func(t *testing.T) {
+		mT := new(mockT)
+		d := NewWithOutput(mT, mT)
+		r := NewRecorder(d)
+		r.ValueIs(
+			[]interface{}{1, "foo"},
+			[]int{1, 2},
+		)
+		mT.AssertCalled(t, "Fail")
+		AssertResultsAre(
+			t,
+			r.record[0].output,
+			[]resultExpect{
+				{
+					pass:     false,
+					dataPath: []string{"[]interface {}", "[1]"},
+				},
+			},
+			"got expected results",
+		)
+		assert.Equal(
+			t,
+			"Cannot convert between a string and an int",
+			r.record[0].output[0].result.description,
+			"got expected description",
+		)
+	})
+}
+
 func TestNameGeneration(t *testing.T) {
 	t.Run("d.Is with no name", func(t *testing.T) {
 		mT := new(mockT)
@@ -0,0 +1,211 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+// CallTester is returned by `d.Call`. It records checks for the values
+// returned by a function, then calls the function and runs those checks when
+// its `Etc` or `End` method is called.
+type CallTester struct {
+	d    *D
+	fn   interface{}
+	args []interface{}
+	// The name of the function, like "strconv.Atoi".
+	name string
+	// The path for failures that are about the call as a whole.
+	path    Path
+	checks  []callCheck
+	returns *callReturns
+}
+
+type callCheck struct {
+	idx    int
+	expect interface{}
+	path   Path
+}
+
+type callReturns struct {
+	count int
+	path  Path
+}
+
+// Call takes a function and the arguments to call it with and returns a
+// CallTester. Use the tester's `Return` and `Returns` methods to say what the
+// function should return, then call `Etc` or `End` to call the function and
+// check its return values. Nothing is checked until `Etc` or `End` is
+// called.
+//
+// The arguments are checked against the function's signature in the same way
+// as the function passed to `d.Func`, so passing the wrong number of
+// arguments or an argument of the wrong type is reported as a usage error.
+func (d *D) Call(fn interface{}, args ...interface{}) *CallTester {
+	name := "fn"
+	if v := reflect.ValueOf(fn); v.Kind() == reflect.Func && !v.IsNil() {
+		if f := runtime.FuncForPC(v.Pointer()); f != nil {
+			name = funcNameRE.ReplaceAllLiteralString(f.Name(), "")
+		}
+	}
+
+	return &CallTester{
+		d:    d,
+		fn:   fn,
+		args: args,
+		name: name,
+		path: d.NewPath(name+"(...)", 0, ""),
+	}
+}
+
+// Return takes the index of a return value and an expected value for it. The
+// expected value can be a literal value or a `Comparer`.
+func (ct *CallTester) Return(idx int, expect interface{}) *CallTester {
+	ct.checks = append(ct.checks, callCheck{
+		idx:    idx,
+		expect: expect,
+		path:   ct.d.NewPath(ct.returnPathData(idx), 0, ""),
+	})
+	return ct
+}
+
+// Returns takes an expected value for every value the function returns, in
+// order. Each expected value can be a literal value or a `Comparer`. If the
+// function returns a different number of values, this is considered a
+// failure.
+func (ct *CallTester) Returns(expect ...interface{}) *CallTester {
+	ct.returns = &callReturns{
+		count: len(expect),
+		path:  ct.d.NewPath(ct.name+"(...)", 0, ""),
+	}
+	for i, e := range expect {
+		ct.checks = append(ct.checks, callCheck{
+			idx:    i,
+			expect: e,
+			path:   ct.d.NewPath(ct.returnPathData(i), 0, ""),
+		})
+	}
+	return ct
+}
+
+// Etc calls the function and checks its return values. Return values which
+// were not given to `Return` or `Returns` are not checked.
+//
+// The arguments follow the same rules as the final arguments to `d.Is`.
+func (ct *CallTester) Etc(args ...interface{}) bool {
+	return ct.run(Etc, args)
+}
+
+// End calls the function and checks its return values. Every return value
+// must have been given to `Return` or `Returns` or else the test will fail.
+//
+// The arguments follow the same rules as the final arguments to `d.Is`.
+func (ct *CallTester) End(args ...interface{}) bool {
+	return ct.run(End, args)
+}
+
+func (ct *CallTester) returnPathData(idx int) string {
+	return fmt.Sprintf("%s(...)→[%d]", ct.name, idx)
+}
+
+func (ct *CallTester) run(ending CollectionEnding, args []interface{}) bool {
+	d := ct.d
+	d.ResetState()
+	name := argsToName(args, "unnamed d.Call test")
+
+	ret, ok := ct.call()
+	if !ok {
+		return d.ok(name)
+	}
+
+	if ct.returns != nil && ct.returns.count != len(ret) {
+		d.PushPath(ct.returns.path)
+		d.AddResult(result{
+			pass:  false,
+			where: inDataStructure,
+			op:    "Returns()",
+			description: fmt.Sprintf(
+				"Returns() was passed %d value(s) but the function returns %d", ct.returns.count, len(ret)),
+		})
+		d.PopPath()
+		return d.ok(name)
+	}
+
+	seen := map[int]bool{}
+	for _, c := range ct.checks {
+		d.PushPath(c.path)
+
+		if c.idx < 0 || c.idx >= len(ret) {
+			d.AddResult(result{
+				pass:  false,
+				where: inDataStructure,
+				op:    fmt.Sprintf("[%d]", c.idx),
+				description: fmt.Sprintf(
+					"Attempted to get a return value (%d) past the end of a function that returns %d value(s)",
+					c.idx, len(ret)),
+			})
+			d.PopPath()
+			continue
+		}
+
+		seen[c.idx] = true
+		d.PushActual(ret[c.idx].Interface())
+		if e, ok := c.expect.(Comparer); ok {
+			e.Compare(d)
+		} else {
+			d.Equal(c.expect).Compare(d)
+		}
+		d.PopActual()
+		d.PopPath()
+	}
+
+	if ending == End {
+		d.PushPath(ct.path)
+		for i := range ret {
+			if !seen[i] {
+				d.AddResult(result{
+					pass:        false,
+					where:       inUsage,
+					description: fmt.Sprintf("Your call test did not check return value %d", i),
+				})
+			}
+		}
+		d.PopPath()
+	}
+
+	return d.ok(name)
+}
+
+// call validates the function and its arguments and then calls it. If the
+// function or arguments are not valid it adds a failure and returns false.
+func (ct *CallTester) call() ([]reflect.Value, bool) {
+	d := ct.d
+	d.PushPath(ct.path)
+	defer d.PopPath()
+
+	usage := func(desc string) ([]reflect.Value, bool) {
+		d.AddResult(result{
+			pass:        false,
+			where:       inUsage,
+			description: desc,
+		})
+		return nil, false
+	}
+
+	v, err := funcValue(ct.fn, "detest.Call()")
+	if err != nil {
+		return usage(err.Error())
+	}
+
+	t := v.Type()
+	if err := checkNumArgs(t, len(ct.args), "the function passed to detest.Call()"); err != nil {
+		return usage(err.Error())
+	}
+
+	in, err := argumentValues(t, ct.args, "detest.Call()", "the function")
+	if err != nil {
+		return usage(err.Error())
+	}
+
+	return v.Call(in), true
+}
@@ -0,0 +1,211 @@
+package detest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCall(t *testing.T) {
+	t.Run("Passing tests", callPassingTests)
+	t.Run("Return fails", callReturnFails)
+	t.Run("Returns with the wrong count", callReturnsWrongCount)
+	t.Run("Return past the end", callReturnPastEnd)
+	t.Run("End with unchecked values", callEndUnchecked)
+	t.Run("Usage errors", callUsageErrors)
+}
+
+func callSum(base int, ns ...int) int {
+	for _, n := range ns {
+		base += n
+	}
+	return base
+}
+
+type callInts []int
+
+func callLen(ns []int) int {
+	return len(ns)
+}
+
+func callPassingTests(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(d *D, name string) bool
+	}{
+		{"Return", func(d *D, name string) bool {
+			return d.Call(strconv.Atoi, "42").Return(0, 42).Return(1, nil).End(name)
+		}},
+		{"Returns", func(d *D, name string) bool {
+			return d.Call(strconv.Atoi, "x").Returns(0, d.AnError()).End(name)
+		}},
+		{"Etc", func(d *D, name string) bool {
+			return d.Call(strconv.Atoi, "x").Return(1, d.ErrorIs(strconv.ErrSyntax)).Etc(name)
+		}},
+		{"Variadic", func(d *D, name string) bool {
+			return d.Call(callSum, 1, 2, 3).Returns(6).End(name)
+		}},
+		{"Nil argument", func(d *D, name string) bool {
+			return d.Call(errors.Unwrap, nil).Returns(nil).End(name)
+		}},
+		{"Assignable argument", func(d *D, name string) bool {
+			return d.Call(callLen, callInts{1, 2}).Returns(2).End(name)
+		}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mT := new(mockT)
+			d := NewWithOutput(mT, mT)
+			assert.True(t, test.run(d, test.name), "test passed")
+			mT.AssertNotCalled(t, "Fail")
+			mT.AssertCalled(t, "WriteString", fmt.Sprintf("Assertion ok: %s\n", test.name))
+		})
+	}
+}
+
+func callReturnFails(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	d.Call(strconv.Atoi, "42").Return(0, 43).Return(1, nil).End("atoi")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		d.state.output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"strconv.Atoi(...)→[0]", "int"}},
+			{pass: true, dataPath: []string{"strconv.Atoi(...)→[1]", "nil"}},
+		},
+		"got expected results",
+	)
+	path := d.state.output[0].result.path[0]
+	assert.Equal(t, "detest.(*CallTester).Return", path.callee, "got expected callee")
+	assert.Equal(t, "detest.callReturnFails", path.caller, "got expected caller")
+}
+
+func callReturnsWrongCount(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	d.Call(strconv.Atoi, "42").Returns(42).End("atoi")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		d.state.output,
+		[]resultExpect{{pass: false, dataPath: []string{"strconv.Atoi(...)"}}},
+		"got expected results",
+	)
+	res := d.state.output[0].result
+	assert.Equal(t, inDataStructure, res.where, "failure is in the data structure")
+	assert.Equal(t, "Returns() was passed 1 value(s) but the function returns 2", res.description, "got expected description")
+}
+
+func callReturnPastEnd(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	d.Call(strconv.Atoi, "42").Return(2, nil).Etc("atoi")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		d.state.output,
+		[]resultExpect{{pass: false, dataPath: []string{"strconv.Atoi(...)→[2]"}}},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Attempted to get a return value (2) past the end of a function that returns 2 value(s)",
+		d.state.output[0].result.description,
+		"got expected description",
+	)
+}
+
+func callEndUnchecked(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	d.Call(strconv.Atoi, "42").Return(0, 42).End("atoi")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		d.state.output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"strconv.Atoi(...)→[0]", "int"}},
+			{pass: false, dataPath: []string{"strconv.Atoi(...)"}},
+		},
+		"got expected results",
+	)
+	res := d.state.output[1].result
+	assert.Equal(t, inUsage, res.where, "failure is a usage error")
+	assert.Equal(t, "Your call test did not check return value 1", res.description, "got expected description")
+}
+
+func callUsageErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		fn          interface{}
+		args        []interface{}
+		description string
+	}{
+		{
+			"Not a function",
+			42,
+			nil,
+			"you passed an int to detest.Call() but it needs a function",
+		},
+		{
+			"Wrong number of arguments",
+			strconv.Atoi,
+			[]interface{}{"1", "2"},
+			"the function passed to detest.Call() takes 1 value, but you passed 2",
+		},
+		{
+			"Too few arguments",
+			strings.Repeat,
+			[]interface{}{"a"},
+			"the function passed to detest.Call() takes 2 values, but you passed 1",
+		},
+		{
+			"Nil function",
+			(func(string) int)(nil),
+			[]interface{}{"1"},
+			"you passed a nil func (string) int to detest.Call() but it needs a non-nil function",
+		},
+		{
+			"Too few arguments for a variadic function",
+			callSum,
+			nil,
+			"the function passed to detest.Call() takes at least 1 value, but you passed 0",
+		},
+		{
+			"Wrong argument type",
+			strconv.Atoi,
+			[]interface{}{1},
+			"argument 0 passed to detest.Call() is an int but the function takes a string",
+		},
+		{
+			"Wrong variadic argument type",
+			callSum,
+			[]interface{}{1, "2"},
+			"argument 1 passed to detest.Call() is a string but the function takes an int",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mT := new(mockT)
+			d := NewWithOutput(mT, mT)
+			d.Call(test.fn, test.args...).Etc(test.name)
+			mT.AssertCalled(t, "Fail")
+			if !assert.Len(t, d.state.output, 1, "one output item") {
+				return
+			}
+			res := d.state.output[0].result
+			assert.Equal(t, inUsage, res.where, "failure is a usage error")
+			assert.Equal(t, test.description, res.description, "got expected description")
+		})
+	}
+}
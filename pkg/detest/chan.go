@@ -0,0 +1,234 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// The time that `Recv`, `Closed`, and `Drain` wait for a value before giving
+// up. Use `RecvWithin` to wait for a different amount of time.
+const defaultRecvTimeout = time.Second
+
+// ChanComparer implements comparison of channel values.
+type ChanComparer struct {
+	with func(*ChanTester)
+}
+
+// Chan takes a function which will be called to do further comparisons of the
+// values received from the channel.
+func (d *D) Chan(with func(*ChanTester)) ChanComparer {
+	return ChanComparer{with}
+}
+
+// ChanTester is the struct that will be passed to the test function passed to
+// detest.Chan. This struct implements the channel-specific testing methods
+// such as Recv() and Closed().
+type ChanTester struct {
+	d *D
+	// The number of values received from the channel so far.
+	received int
+}
+
+// Compare compares the channel value in d.Actual() by calling the function
+// passed to `Chan()`, which is in turn expected to receive values from the
+// channel and test them.
+func (cc ChanComparer) Compare(d *D) {
+	v := reflect.ValueOf(d.Actual())
+	d.PushPath(d.NewPath(describeTypeOfReflectValue(v), 1, "detest.(*D).Chan"))
+	defer d.PopPath()
+
+	if !v.IsValid() || v.Kind() != reflect.Chan {
+		d.AddResult(result{
+			actual: newValue(d.Actual()),
+			pass:   false,
+			where:  inDataStructure,
+			op:     "<-",
+			description: fmt.Sprintf(
+				"Called detest.Chan() but the value being tested isn't a channel, it's %s",
+				articleize(describeTypeOfReflectValue(v)),
+			),
+		})
+		return
+	}
+
+	if v.Type().ChanDir()&reflect.RecvDir == 0 {
+		d.AddResult(result{
+			actual:      newValue(d.Actual()),
+			pass:        false,
+			where:       inDataStructure,
+			op:          "<-",
+			description: "Called detest.Chan() but the value being tested is a send-only channel",
+		})
+		return
+	}
+
+	cc.with(&ChanTester{d: d})
+}
+
+// Recv receives a value from the channel and compares it to the expected
+// value. If no value is received within one second, or the channel is closed,
+// this is considered a failure.
+func (ct *ChanTester) Recv(expect interface{}) {
+	ct.recvAndCompare(defaultRecvTimeout, expect)
+}
+
+// RecvWithin is like Recv, but it waits for the given amount of time for a
+// value instead of one second.
+func (ct *ChanTester) RecvWithin(timeout time.Duration, expect interface{}) {
+	ct.recvAndCompare(timeout, expect)
+}
+
+func (ct *ChanTester) recvAndCompare(timeout time.Duration, expect interface{}) {
+	ct.d.PushPath(ct.d.NewPath(ct.nextPathData(), 1, ""))
+	defer ct.d.PopPath()
+
+	val, ok := ct.recv(timeout)
+	if !ok {
+		return
+	}
+
+	ct.d.PushActual(val)
+	defer ct.d.PopActual()
+
+	if c, ok := expect.(Comparer); ok {
+		c.Compare(ct.d)
+	} else {
+		ct.d.Equal(expect).Compare(ct.d)
+	}
+}
+
+// Drain receives values from the channel until it is closed, passing each one
+// to the given comparer. If the channel is not closed, this will fail once
+// no value has been received for one second.
+func (ct *ChanTester) Drain(expect Comparer) {
+	ch := reflect.ValueOf(ct.d.Actual())
+	for {
+		ct.d.PushPath(ct.d.NewPath(ct.nextPathData(), 0, ""))
+
+		val, ok, timedOut := recvWithTimeout(ch, defaultRecvTimeout)
+		if timedOut {
+			ct.d.AddResult(ct.timeoutResult(defaultRecvTimeout))
+			ct.d.PopPath()
+			return
+		}
+		if !ok {
+			ct.d.PopPath()
+			return
+		}
+		ct.received++
+
+		ct.d.PushActual(val.Interface())
+		expect.Compare(ct.d)
+		ct.d.PopActual()
+		ct.d.PopPath()
+	}
+}
+
+// Closed checks that the channel is closed without any more values to
+// receive. If a value is received instead, or the channel is still open
+// after one second, this is considered a failure.
+func (ct *ChanTester) Closed() {
+	ct.d.PushPath(ct.d.NewPath("closed", 0, ""))
+	defer ct.d.PopPath()
+
+	val, ok, timedOut := recvWithTimeout(reflect.ValueOf(ct.d.Actual()), defaultRecvTimeout)
+
+	result := result{
+		actual: newValue(ct.d.Actual()),
+		op:     "closed",
+		pass:   !ok && !timedOut,
+	}
+	if timedOut {
+		result.where = inDataStructure
+		result.description = fmt.Sprintf(
+			"The channel was still open after waiting %s for it to be closed", defaultRecvTimeout)
+	} else if ok {
+		ct.received++
+		result.where = inDataStructure
+		result.description = fmt.Sprintf(
+			"Expected the channel to be closed but received another value (%v)", val.Interface())
+	}
+	ct.d.AddResult(result)
+}
+
+// Empty checks that there is no value ready to be received from the channel
+// right now. A closed channel with no buffered values is empty. If a value is
+// ready it is received, and this is considered a failure.
+func (ct *ChanTester) Empty() {
+	ct.d.PushPath(ct.d.NewPath("empty", 0, ""))
+	defer ct.d.PopPath()
+
+	chosen, val, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ct.d.Actual())},
+		{Dir: reflect.SelectDefault},
+	})
+
+	result := result{
+		actual: newValue(ct.d.Actual()),
+		op:     "empty",
+		pass:   chosen != 0 || !ok,
+	}
+	if !result.pass {
+		ct.received++
+		result.where = inDataStructure
+		result.description = fmt.Sprintf(
+			"Expected the channel to be empty but received a value (%v)", val.Interface())
+	}
+	ct.d.AddResult(result)
+}
+
+func (ct *ChanTester) nextPathData() string {
+	return fmt.Sprintf("<-#%d", ct.received+1)
+}
+
+// recv receives a value from the channel in d.Actual(). If the channel is
+// closed or the timeout expires, it adds a failure and returns false.
+func (ct *ChanTester) recv(timeout time.Duration) (interface{}, bool) {
+	val, ok, timedOut := recvWithTimeout(reflect.ValueOf(ct.d.Actual()), timeout)
+	if timedOut {
+		ct.d.AddResult(ct.timeoutResult(timeout))
+		return nil, false
+	}
+	if !ok {
+		ct.d.AddResult(result{
+			actual: newValue(ct.d.Actual()),
+			pass:   false,
+			where:  inDataStructure,
+			op:     "<-",
+			description: fmt.Sprintf(
+				"The channel was closed after %d value(s) were received from it", ct.received),
+		})
+		return nil, false
+	}
+
+	ct.received++
+	return val.Interface(), true
+}
+
+func (ct *ChanTester) timeoutResult(timeout time.Duration) result {
+	return result{
+		actual: newValue(ct.d.Actual()),
+		pass:   false,
+		where:  inDataStructure,
+		op:     "<-",
+		description: fmt.Sprintf(
+			"Timed out after %s waiting to receive a value from the channel", timeout),
+	}
+}
+
+// recvWithTimeout receives from ch, giving up after the timeout. The ok
+// return value is false if the channel was closed.
+func recvWithTimeout(ch reflect.Value, timeout time.Duration) (val reflect.Value, ok, timedOut bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	chosen, val, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)},
+	})
+	if chosen == 1 {
+		return reflect.Value{}, false, true
+	}
+	return val, ok, false
+}
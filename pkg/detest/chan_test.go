@@ -0,0 +1,355 @@
+package detest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChan(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"Passing test", chanPassingTest},
+		{"Producer goroutine", chanProducerGoroutine},
+		{"Failing test", chanFailingTest},
+		{"Passed non-channel to Chan", chanPassedNonChannel},
+		{"Passed send-only channel to Chan", chanPassedSendOnlyChannel},
+		{"RecvWithin times out", chanRecvWithinTimesOut},
+		{"Recv on closed channel", chanRecvOnClosedChannel},
+		{"Closed fails", chanClosedFails},
+		{"Empty fails", chanEmptyFails},
+		{"Drain fails", chanDrainFails},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+func chanPassingTest(t *testing.T) {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	close(ch)
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		ch,
+		d.Chan(func(ct *ChanTester) {
+			ct.Recv(1)
+			ct.Recv(d.GT(1))
+			ct.Empty()
+			ct.Closed()
+		}),
+		"chan",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: chan\n")
+}
+
+func chanProducerGoroutine(t *testing.T) {
+	ch := make(chan string)
+	go func() {
+		for _, s := range []string{"a", "b", "c"} {
+			ch <- s
+		}
+		close(ch)
+	}()
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		(<-chan string)(ch),
+		r.Chan(func(ct *ChanTester) {
+			ct.RecvWithin(5*time.Second, "a")
+			ct.Drain(r.In("b", "c"))
+		}),
+		"producer",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"chan(string)", "<-#1", "string"}},
+			{pass: true, dataPath: []string{"chan(string)", "<-#2", "string"}},
+			{pass: true, dataPath: []string{"chan(string)", "<-#3", "string"}},
+		},
+		"got expected results",
+	)
+}
+
+func chanFailingTest(t *testing.T) {
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		ch,
+		r.Chan(func(ct *ChanTester) {
+			ct.Recv(1)
+			ct.Recv(3)
+		}),
+		"chan",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 2, "record has state with two output items")
+	assert.Equal(
+		t,
+		&result{
+			actual: &value{value: 2, desc: "int"},
+			expect: &value{value: 3, desc: "int"},
+			op:     "==",
+			pass:   false,
+			path: []Path{
+				{
+					data:   "chan(int)",
+					callee: "detest.(*D).Chan",
+					caller: "detest.(*DetestRecorder).Is",
+				},
+				{
+					data:   "<-#2",
+					callee: "detest.(*ChanTester).Recv",
+					caller: "detest.chanFailingTest.func1",
+				},
+				{
+					data:   "int",
+					callee: "detest.(*D).Equal",
+					caller: "detest.(*ChanTester).Recv",
+				},
+			},
+			where:       inValue,
+			description: "",
+		},
+		r.record[0].output[1].result,
+		"got the expected result",
+	)
+}
+
+func chanPassedNonChannel(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]int{1},
+		r.Chan(func(ct *ChanTester) {
+			ct.Recv(1)
+		}),
+		"non-channel",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{{pass: false, dataPath: []string{"[]int"}}},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Called detest.Chan() but the value being tested isn't a channel, it's a []int",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func chanPassedSendOnlyChannel(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		make(chan<- int),
+		r.Chan(func(ct *ChanTester) {
+			ct.Recv(1)
+		}),
+		"send-only",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	assert.Equal(
+		t,
+		"Called detest.Chan() but the value being tested is a send-only channel",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func chanRecvWithinTimesOut(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		make(chan int),
+		r.Chan(func(ct *ChanTester) {
+			ct.RecvWithin(10*time.Millisecond, 1)
+		}),
+		"timeout",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{{pass: false, dataPath: []string{"chan(int)", "<-#1"}}},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, inDataStructure, res.where, "failure is in the data structure")
+	assert.Equal(
+		t,
+		"Timed out after 10ms waiting to receive a value from the channel",
+		res.description,
+		"got expected description",
+	)
+	assert.Equal(
+		t,
+		Path{
+			data:   "<-#1",
+			callee: "detest.(*ChanTester).RecvWithin",
+			caller: "detest.chanRecvWithinTimesOut.func1",
+		},
+		res.path[1],
+		"got expected path for the receive",
+	)
+}
+
+func chanRecvOnClosedChannel(t *testing.T) {
+	ch := make(chan int, 1)
+	ch <- 1
+	close(ch)
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		ch,
+		r.Chan(func(ct *ChanTester) {
+			ct.Recv(1)
+			ct.Recv(2)
+		}),
+		"closed",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"chan(int)", "<-#1", "int"}},
+			{pass: false, dataPath: []string{"chan(int)", "<-#2"}},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[1].result
+	assert.Equal(t, inDataStructure, res.where, "failure is in the data structure")
+	assert.Equal(
+		t,
+		"The channel was closed after 1 value(s) were received from it",
+		res.description,
+		"got expected description",
+	)
+}
+
+func chanClosedFails(t *testing.T) {
+	ch := make(chan int, 1)
+	ch <- 42
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		ch,
+		r.Chan(func(ct *ChanTester) {
+			ct.Closed()
+		}),
+		"closed",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{{pass: false, dataPath: []string{"chan(int)", "closed"}}},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Expected the channel to be closed but received another value (42)",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func chanEmptyFails(t *testing.T) {
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		ch,
+		r.Chan(func(ct *ChanTester) {
+			ct.Recv(1)
+			ct.Empty()
+		}),
+		"empty",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"chan(int)", "<-#1", "int"}},
+			{pass: false, dataPath: []string{"chan(int)", "empty"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Expected the channel to be empty but received a value (2)",
+		r.record[0].output[1].result.description,
+		"got expected description",
+	)
+}
+
+func chanDrainFails(t *testing.T) {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 20
+	ch <- 3
+	close(ch)
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		ch,
+		r.Chan(func(ct *ChanTester) {
+			ct.Drain(r.LT(10))
+		}),
+		"drain",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"chan(int)", "<-#1", "int"}},
+			{pass: false, dataPath: []string{"chan(int)", "<-#2", "int"}},
+			{pass: true, dataPath: []string{"chan(int)", "<-#3", "int"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"detest.chanDrainFails.func1",
+		r.record[0].output[1].result.path[1].caller,
+		"caller is the test function",
+	)
+}
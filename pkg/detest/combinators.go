@@ -0,0 +1,189 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AllOfComparer implements a check that a value passes every one of a list of
+// comparers.
+type AllOfComparer struct {
+	comparers []Comparer
+}
+
+// AllOf takes any number of comparers and returns an AllOfComparer which
+// passes if the actual value passes all of them. Every comparer is always
+// run, so the test output includes every failure.
+func (d *D) AllOf(comparers ...Comparer) AllOfComparer {
+	return AllOfComparer{comparers}
+}
+
+// Compare runs each of the comparers passed to AllOf() against the value in
+// d.Actual().
+func (ac AllOfComparer) Compare(d *D) {
+	path := d.NewPath("AllOf", 1, "detest.(*D).AllOf")
+	d.PushPath(path)
+	defer d.PopPath()
+
+	// With no comparers there are no results, so we add one ourselves.
+	if len(ac.comparers) == 0 {
+		d.AddResult(result{
+			actual: newValue(d.Actual()),
+			pass:   true,
+			op:     "AllOf",
+		})
+		return
+	}
+
+	for i, c := range ac.comparers {
+		d.PushPath(alternativePath(path, i))
+		c.Compare(d)
+		d.PopPath()
+	}
+}
+
+// AnyOfComparer implements a check that a value passes at least one of a list
+// of comparers.
+type AnyOfComparer struct {
+	comparers []Comparer
+}
+
+// AnyOf takes any number of comparers and returns an AnyOfComparer which
+// passes if the actual value passes at least one of them. The comparers are
+// tried in order, stopping at the first one that passes. If none of them
+// pass, the test output includes the failures from every comparer.
+func (d *D) AnyOf(comparers ...Comparer) AnyOfComparer {
+	return AnyOfComparer{comparers}
+}
+
+// Compare runs the comparers passed to AnyOf() against the value in
+// d.Actual() until one of them passes.
+func (ac AnyOfComparer) Compare(d *D) {
+	path := d.NewPath("AnyOf", 1, "detest.(*D).AnyOf")
+	d.PushPath(path)
+	defer d.PopPath()
+
+	var failures []outputItem
+	for i, c := range ac.comparers {
+		d.PushPath(alternativePath(path, i))
+		pass, output := d.evaluate(c)
+		d.PopPath()
+
+		if pass {
+			d.state.output = append(d.state.output, output...)
+			return
+		}
+		failures = append(failures, output...)
+	}
+
+	d.AddResult(result{
+		actual: newValue(d.Actual()),
+		pass:   false,
+		where:  inValue,
+		op:     "AnyOf",
+		description: fmt.Sprintf(
+			"None of the %d comparers passed to AnyOf() passed. The failure for each one follows.",
+			len(ac.comparers),
+		),
+	})
+	d.state.output = append(d.state.output, failures...)
+}
+
+// NotComparer implements a check that a value does not pass a comparer.
+type NotComparer struct {
+	comparer Comparer
+}
+
+// Not takes a comparer and returns a NotComparer which passes if the actual
+// value fails that comparer. If the comparer fails because it was used
+// incorrectly, for example by passing a non-function to `AllValues`, then
+// the `Not` fails as well.
+func (d *D) Not(c Comparer) NotComparer {
+	return NotComparer{c}
+}
+
+// Compare runs the comparer passed to Not() against the value in d.Actual()
+// and inverts its result.
+func (nc NotComparer) Compare(d *D) {
+	d.PushPath(d.NewPath("Not", 1, "detest.(*D).Not"))
+	defer d.PopPath()
+
+	pass, output := d.evaluate(nc.comparer)
+
+	// Usage errors are always reported. We also keep any warnings.
+	for _, o := range output {
+		if o.result == nil || (!o.result.pass && o.result.where == inUsage) {
+			d.state.output = append(d.state.output, o)
+		}
+	}
+	if hasUsageError(output) {
+		return
+	}
+
+	result := result{
+		actual: newValue(d.Actual()),
+		pass:   !pass,
+		op:     "Not",
+	}
+	if pass {
+		result.where = inValue
+		result.description = "The comparer passed to Not() passed, but it should have failed"
+	}
+	d.AddResult(result)
+}
+
+func hasUsageError(output []outputItem) bool {
+	for _, o := range output {
+		if o.result != nil && !o.result.pass && o.result.where == inUsage {
+			return true
+		}
+	}
+	return false
+}
+
+// InComparer implements a check that a value is one of a set of values.
+type InComparer struct {
+	values []interface{}
+}
+
+// In takes any number of values and returns an InComparer which passes if the
+// actual value is exactly equal to one of them, using the same comparison as
+// `d.Is`.
+func (d *D) In(values ...interface{}) InComparer {
+	return InComparer{values}
+}
+
+// Compare checks whether the value in d.Actual() is equal to one of the values
+// passed to In().
+func (ic InComparer) Compare(d *D) {
+	actual := d.Actual()
+	d.PushPath(d.NewPath(describeTypeOfReflectValue(reflect.ValueOf(actual)), 1, "detest.(*D).In"))
+	defer d.PopPath()
+
+	result := result{
+		actual: newValue(actual),
+		expect: newValue(ic.values),
+		op:     "in",
+	}
+
+	for _, v := range ic.values {
+		if pass, _ := d.evaluate(d.Equal(v)); pass {
+			result.pass = true
+			d.AddResult(result)
+			return
+		}
+	}
+
+	result.where = inValue
+	d.AddResult(result)
+}
+
+// alternativePath returns a path element for the comparer at the given index
+// in a combinator, with the same caller as the combinator itself.
+func alternativePath(combinator Path, i int) Path {
+	return Path{
+		data:   fmt.Sprintf("[%d]", i),
+		callee: combinator.callee,
+		caller: combinator.caller,
+	}
+}
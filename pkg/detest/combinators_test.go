@@ -0,0 +1,233 @@
+package detest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombinators(t *testing.T) {
+	t.Run("Passing comparisons", combinatorsPassingTests)
+	t.Run("AllOf reports every failure", combinatorsAllOfFailures)
+	t.Run("AnyOf reports every alternative", combinatorsAnyOfFailures)
+	t.Run("AnyOf discards earlier failures", combinatorsAnyOfDiscardsFailures)
+	t.Run("Not fails when the comparer passes", combinatorsNotFails)
+	t.Run("Not reports usage errors", combinatorsNotUsageError)
+	t.Run("In fails", combinatorsInFails)
+	t.Run("Nested combinators", combinatorsNested)
+}
+
+func combinatorsPassingTests(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   interface{}
+		comparer func(d *D) Comparer
+	}{
+		{"AllOf", 5, func(d *D) Comparer { return d.AllOf(d.GT(1), d.LT(10)) }},
+		{"AllOf with no comparers", 5, func(d *D) Comparer { return d.AllOf() }},
+		{"AnyOf with first passing", 5, func(d *D) Comparer { return d.AnyOf(d.GT(1), d.LT(1)) }},
+		{"AnyOf with last passing", 5, func(d *D) Comparer { return d.AnyOf(d.GT(10), d.LT(10)) }},
+		{"Not", 5, func(d *D) Comparer { return d.Not(d.GT(10)) }},
+		{"Not with type mismatch", "foo", func(d *D) Comparer { return d.Not(d.GT(10)) }},
+		{"In", 5, func(d *D) Comparer { return d.In(1, 3, 5) }},
+		{"In with nested values", []int{1, 2}, func(d *D) Comparer { return d.In([]int{1}, []int{1, 2}) }},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mT := new(mockT)
+			d := NewWithOutput(mT, mT)
+			d.Passes(test.actual, test.comparer(d), test.name)
+			mT.AssertNotCalled(t, "Fail")
+			mT.AssertCalled(t, "WriteString", fmt.Sprintf("Assertion ok: %s\n", test.name))
+		})
+	}
+}
+
+func combinatorsAllOfFailures(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.Passes(15, r.AllOf(r.GT(20), r.LT(10), r.GT(0)), "AllOf")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"AllOf", "[0]", "int"},
+			},
+			{
+				pass:     false,
+				dataPath: []string{"AllOf", "[1]", "int"},
+			},
+			{
+				pass:     true,
+				dataPath: []string{"AllOf", "[2]", "int"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(t, "detest.(*D).AllOf", r.record[0].output[0].result.path[0].callee, "got expected callee")
+}
+
+func combinatorsAnyOfFailures(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.Passes(15, r.AnyOf(r.GT(20), r.In(1, 2)), "AnyOf")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"AnyOf"},
+			},
+			{
+				pass:     false,
+				dataPath: []string{"AnyOf", "[0]", "int"},
+			},
+			{
+				pass:     false,
+				dataPath: []string{"AnyOf", "[1]", "int"},
+			},
+		},
+		"got expected results",
+	)
+	output := r.record[0].output
+	assert.Equal(
+		t,
+		"None of the 2 comparers passed to AnyOf() passed. The failure for each one follows.",
+		output[0].result.description,
+		"got expected description",
+	)
+	assert.Equal(t, ">", output[1].result.op, "first alternative's failure is from GT")
+	assert.Equal(t, "in", output[2].result.op, "second alternative's failure is from In")
+}
+
+func combinatorsAnyOfDiscardsFailures(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.Passes(15, r.AnyOf(r.GT(20), r.LT(20), r.GT(30)), "AnyOf")
+	mT.AssertNotCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     true,
+				dataPath: []string{"AnyOf", "[1]", "int"},
+			},
+		},
+		"got expected results",
+	)
+}
+
+func combinatorsNotFails(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.Passes(15, r.Not(r.GT(10)), "Not")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"Not"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"The comparer passed to Not() passed, but it should have failed",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func combinatorsNotUsageError(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.Passes(
+		[]int{1},
+		r.Not(r.Slice(func(st *SliceTester) {
+			st.AllValues(42)
+			st.End()
+		})),
+		"Not",
+	)
+	mT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "one output item")
+	assert.Equal(t, inUsage, r.record[0].output[0].result.where, "failure is a usage error")
+}
+
+func combinatorsInFails(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.Passes(4, r.In(1, 3, 5), "In")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"int"},
+			},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, "in", res.op, "got expected op")
+	assert.Equal(t, []interface{}{1, 3, 5}, res.expect.value, "expect shows all the values")
+	assert.Equal(t, "detest.(*D).In", res.path[0].callee, "got expected callee")
+}
+
+func combinatorsNested(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string]int{"status": 503},
+		r.Map(func(mt *MapTester) {
+			mt.Key("status", r.AnyOf(r.In(200, 204), r.AllOf(r.GTE(300), r.LT(400))))
+			mt.End()
+		}),
+		"nested combinators",
+	)
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"map[string]int", "[status]", "AnyOf"},
+			},
+			{
+				pass:     false,
+				dataPath: []string{"map[string]int", "[status]", "AnyOf", "[0]", "int"},
+			},
+			{
+				pass:     true,
+				dataPath: []string{"map[string]int", "[status]", "AnyOf", "[1]", "AllOf", "[0]", "int"},
+			},
+			{
+				pass:     false,
+				dataPath: []string{"map[string]int", "[status]", "AnyOf", "[1]", "AllOf", "[1]", "int"},
+			},
+		},
+		"got expected results",
+	)
+}
@@ -0,0 +1,589 @@
+package detest
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// deepEqualer walks two values of the same type in parallel. Every time it
+// steps into a slice, array, map, struct, or pointer it pushes a new path
+// element, so that a mismatch deep inside a data structure is reported with
+// the exact path to the element that differs.
+//
+// Self-referential data structures are handled by keeping track of every pair
+// of pointers (or maps or slices) that we've already compared. A pair that
+// we've already seen is treated as equal, since any difference inside it will
+// be found the first time we compare it.
+//
+// If byValue is true then values of different types are converted to a
+// common type before comparing them, the same way `d.ValueIs` does.
+//
+// Any struct fields or values which are skipped because of the comparison
+// options or a `detest` struct tag are tracked so that we can show them in
+// the output for failures.
+type deepEqualer struct {
+	d         *D
+	top       Path
+	op        string
+	record    bool
+	byValue   bool
+	opts      equalOptions
+	skipped   []string
+	trail     []string
+	visited   map[visit]bool
+	ancestors map[reference]string
+}
+
+// visit is a pair of references that we've already compared. Slices that
+// share a backing array have the same pointer, so for slices we also need
+// the lengths to tell them apart.
+type visit struct {
+	actual    uintptr
+	expect    uintptr
+	actualLen int
+	expectLen int
+	typ       reflect.Type
+}
+
+// reference identifies a pointer, map, or slice in the actual value.
+type reference struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// newDeepEqualer returns a deepEqualer which uses the caller and callee from
+// the given path for all of the path elements it pushes. If record is true,
+// then every mismatch is added to the `*D` as a failed result. Otherwise the
+// comparison stops at the first mismatch without recording anything.
+func newDeepEqualer(d *D, top Path, op string, record bool) *deepEqualer {
+	return &deepEqualer{
+		d:         d,
+		top:       top,
+		op:        op,
+		record:    record,
+		visited:   map[visit]bool{},
+		ancestors: map[reference]string{},
+	}
+}
+
+// run compares the two values and returns true if they are equal. If any
+// values were skipped, the list of skipped paths is attached to every failed
+// result from this comparison.
+func (de *deepEqualer) run(actual, expect interface{}) bool {
+	start := len(de.d.state.output)
+	equal := de.compare(reflect.ValueOf(actual), reflect.ValueOf(expect))
+
+	if len(de.skipped) > 0 {
+		for _, o := range de.d.state.output[start:] {
+			if o.result != nil && !o.result.pass {
+				o.result.skipped = de.skipped
+			}
+		}
+	}
+
+	return equal
+}
+
+func (de *deepEqualer) skip() {
+	if de.record {
+		de.skipped = append(de.skipped, de.trailString())
+	}
+}
+
+func (de *deepEqualer) pushPath(data string) {
+	de.d.PushPath(Path{data: data, callee: de.top.callee, caller: de.top.caller})
+	de.trail = append(de.trail, data)
+}
+
+func (de *deepEqualer) popPath() {
+	de.d.PopPath()
+	de.trail = de.trail[:len(de.trail)-1]
+}
+
+// hasVisited returns true if we've already compared this pair of references.
+// If we haven't, the pair is marked as visited.
+func (de *deepEqualer) hasVisited(actual, expect reflect.Value) bool {
+	v := visit{actual: actual.Pointer(), expect: expect.Pointer(), typ: actual.Type()}
+	if actual.Kind() == reflect.Slice {
+		v.actualLen = actual.Len()
+		v.expectLen = expect.Len()
+	}
+	if de.visited[v] {
+		return true
+	}
+	de.visited[v] = true
+	return false
+}
+
+// enter records that we're descending into the given reference in the actual
+// value. If that reference is one of the values we're already inside of, we
+// have found a cycle, and this returns a path element pointing back to the
+// place where we first saw that reference. The returned func must be called
+// when we're done with this reference.
+func (de *deepEqualer) enter(actual reflect.Value) (string, func()) {
+	r := reference{actual.Pointer(), actual.Type()}
+	if to, ok := de.ancestors[r]; ok {
+		return fmt.Sprintf("<cycle to %s>", to), func() {}
+	}
+
+	de.ancestors[r] = de.trailString()
+	return "", func() { delete(de.ancestors, r) }
+}
+
+// trailString returns the path elements we've pushed as a single string like
+// `[2].Next`. Pointer dereferences and cycle markers are left out.
+func (de *deepEqualer) trailString() string {
+	var trail strings.Builder
+	for _, t := range de.trail {
+		if t == "*" || strings.HasPrefix(t, "<") {
+			continue
+		}
+		trail.WriteString(t)
+	}
+	if trail.Len() == 0 {
+		return de.top.data
+	}
+	return trail.String()
+}
+
+func (de *deepEqualer) compare(actual, expect reflect.Value) bool {
+	if de.byValue {
+		actual = unwrapInterface(actual)
+		expect = unwrapInterface(expect)
+	}
+
+	if !actual.IsValid() || !expect.IsValid() {
+		if actual.IsValid() == expect.IsValid() ||
+			(de.byValue && nilValuesAreEqual(interfaceOrNil(actual), interfaceOrNil(expect))) {
+			return true
+		}
+		return de.fail(actual, expect, inValue, "")
+	}
+
+	if de.opts.ignoresType(actual.Type()) {
+		de.skip()
+		return true
+	}
+
+	if actual.Type() != expect.Type() {
+		if de.byValue {
+			return de.compareDifferentTypes(actual, expect)
+		}
+		return de.fail(actual, expect, inType, "")
+	}
+
+	if found, equal := de.compareWithRegistry(actual, expect); found {
+		return equal
+	}
+
+	// nolint: exhaustive
+	switch actual.Kind() {
+	case reflect.Array:
+		return de.compareElements(actual, expect, actual.Len())
+	case reflect.Slice:
+		return de.compareSlices(actual, expect)
+	case reflect.Map:
+		return de.compareMaps(actual, expect)
+	case reflect.Struct:
+		return de.compareStructs(actual, expect)
+	case reflect.Ptr:
+		return de.comparePointers(actual, expect)
+	case reflect.Interface:
+		if actual.IsNil() || expect.IsNil() {
+			if actual.IsNil() == expect.IsNil() {
+				return true
+			}
+			return de.fail(actual, expect, inValue, "")
+		}
+		return de.compare(actual.Elem(), expect.Elem())
+	case reflect.Func:
+		// This matches the semantics of reflect.DeepEqual, where funcs are
+		// only equal if they are both nil.
+		if actual.IsNil() && expect.IsNil() {
+			return true
+		}
+		return de.fail(actual, expect, inValue, "Functions are only equal to each other if both are nil")
+	}
+
+	if actual.Interface() == expect.Interface() {
+		return true
+	}
+	return de.fail(actual, expect, inValue, "")
+}
+
+// compareDifferentTypes compares two values of different types by value.
+// Numbers are converted using the same rules as `d.ValueIs`. Containers of the
+// same kind are compared element by element, and anything else is compared
+// after converting the expected value to the actual value's type, if that's
+// possible.
+func (de *deepEqualer) compareDifferentTypes(actual, expect reflect.Value) bool {
+	actualNumeric := isNumeric(actual)
+	expectNumeric := isNumeric(expect)
+	if actualNumeric != nil && expectNumeric != nil {
+		actualVal, expectVal, desc := safelyConvertNumberTypes(actual, expect, actualNumeric, expectNumeric)
+		if desc != "" {
+			return de.fail(actual, expect, inType, desc)
+		}
+		if actualVal.Interface() == expectVal.Interface() {
+			return true
+		}
+		return de.fail(actual, expect, inValue, "")
+	}
+
+	if actual.Kind() == expect.Kind() {
+		// nolint: exhaustive
+		switch actual.Kind() {
+		case reflect.Array:
+			if actual.Len() != expect.Len() {
+				return de.fail(
+					actual, expect, inDataStructure,
+					fmt.Sprintf(
+						"The actual array has %d elements but the expected array has %d",
+						actual.Len(), expect.Len(),
+					),
+				)
+			}
+			return de.compareElements(actual, expect, actual.Len())
+		case reflect.Slice:
+			return de.compareSlices(actual, expect)
+		case reflect.Map:
+			return de.compareMaps(actual, expect)
+		case reflect.Ptr:
+			return de.comparePointers(actual, expect)
+		case reflect.Struct:
+			if actual.Type().ConvertibleTo(expect.Type()) {
+				return de.compareStructs(actual, expect)
+			}
+		}
+	}
+
+	if actualNumeric == nil && expectNumeric == nil &&
+		actual.Type().ConvertibleTo(expect.Type()) &&
+		expect.Type().ConvertibleTo(actual.Type()) {
+		return de.compare(actual, expect.Convert(actual.Type()))
+	}
+
+	return de.fail(actual, expect, inType, cannotConvertMessage(actual.Type(), expect.Type()))
+}
+
+func (de *deepEqualer) compareElements(actual, expect reflect.Value, n int) bool {
+	equal := true
+	for i := 0; i < n; i++ {
+		de.pushPath(fmt.Sprintf("[%d]", i))
+		if !de.compare(actual.Index(i), expect.Index(i)) {
+			equal = false
+		}
+		de.popPath()
+
+		if !equal && !de.record {
+			return false
+		}
+	}
+	return equal
+}
+
+func (de *deepEqualer) compareSlices(actual, expect reflect.Value) bool {
+	if actual.IsNil() != expect.IsNil() {
+		return de.fail(
+			actual, expect, inValue,
+			fmt.Sprintf("Expected %s slice but got %s slice", nilness(expect), nilness(actual)),
+		)
+	}
+
+	if actual.Type().Elem().Kind() == reflect.Uint8 && expect.Type().Elem().Kind() == reflect.Uint8 {
+		return de.compareBytes(actual, expect)
+	}
+
+	if actual.Len() == expect.Len() && actual.Pointer() == expect.Pointer() {
+		return true
+	}
+
+	if actual.Len() > 0 && expect.Len() > 0 {
+		if de.hasVisited(actual, expect) {
+			return true
+		}
+		cycle, leave := de.enter(actual)
+		defer leave()
+		if cycle != "" {
+			de.pushPath(cycle)
+			defer de.popPath()
+		}
+	}
+
+	equal := true
+	n := actual.Len()
+	if actual.Len() != expect.Len() {
+		de.fail(
+			actual, expect, inDataStructure,
+			fmt.Sprintf(
+				"The actual slice has %d elements but the expected slice has %d",
+				actual.Len(), expect.Len(),
+			),
+		)
+		if !de.record {
+			return false
+		}
+		equal = false
+		if expect.Len() < n {
+			n = expect.Len()
+		}
+	}
+
+	return de.compareElements(actual, expect, n) && equal
+}
+
+// compareBytes compares byte slices as a single value, rather than element by
+// element. On failure, the path includes the offset of the first differing
+// byte so that it's easy to find in the hex dump of the two slices.
+func (de *deepEqualer) compareBytes(actual, expect reflect.Value) bool {
+	a, e := actual.Bytes(), expect.Bytes()
+	if bytes.Equal(a, e) {
+		return true
+	}
+
+	offset := firstDifference(a, e)
+	de.pushPath(fmt.Sprintf("[%#x]", offset))
+	defer de.popPath()
+
+	desc := fmt.Sprintf("The byte slices first differ at offset %#x (%d)", offset, offset)
+	if len(a) != len(e) {
+		desc += fmt.Sprintf(". The actual slice has %d bytes but the expected slice has %d", len(a), len(e))
+	}
+	return de.fail(actual, expect, inValue, desc)
+}
+
+func firstDifference(a, e []byte) int {
+	for i := 0; i < len(a) && i < len(e); i++ {
+		if a[i] != e[i] {
+			return i
+		}
+	}
+	if len(a) < len(e) {
+		return len(a)
+	}
+	return len(e)
+}
+
+func (de *deepEqualer) compareMaps(actual, expect reflect.Value) bool {
+	if actual.IsNil() != expect.IsNil() {
+		return de.fail(
+			actual, expect, inValue,
+			fmt.Sprintf("Expected %s map but got %s map", nilness(expect), nilness(actual)),
+		)
+	}
+
+	if actual.Len() == expect.Len() && actual.Pointer() == expect.Pointer() {
+		return true
+	}
+
+	if de.hasVisited(actual, expect) {
+		return true
+	}
+	cycle, leave := de.enter(actual)
+	defer leave()
+	if cycle != "" {
+		de.pushPath(cycle)
+		defer de.popPath()
+	}
+
+	if actual.Type().Key() != expect.Type().Key() {
+		converted, ok := convertMapKeys(expect, actual.Type().Key())
+		if !ok {
+			return de.fail(
+				actual, expect, inType,
+				cannotConvertMessage(actual.Type().Key(), expect.Type().Key()),
+			)
+		}
+		expect = converted
+	}
+
+	equal := true
+	for _, k := range sortedKeys(actual, expect) {
+		av := actual.MapIndex(k)
+		ev := expect.MapIndex(k)
+
+		de.pushPath(fmt.Sprintf("[%v]", k))
+		// nolint: gocritic
+		if !ev.IsValid() {
+			de.fail(
+				av, ev, inDataStructure,
+				fmt.Sprintf("The actual map contains a key (%v) that is not in the expected map", k),
+			)
+			equal = false
+		} else if !av.IsValid() {
+			de.fail(
+				av, ev, inDataStructure,
+				fmt.Sprintf("The actual map does not contain the expected key %v", k),
+			)
+			equal = false
+		} else if !de.compare(av, ev) {
+			equal = false
+		}
+		de.popPath()
+
+		if !equal && !de.record {
+			return false
+		}
+	}
+
+	return equal
+}
+
+// convertMapKeys returns a copy of the given map with its keys converted to
+// the given type. If a key cannot be converted without losing information,
+// like converting `1.5` to an int, then it is left out of the returned map, so
+// that it shows up as a key that isn't in the actual map.
+func convertMapKeys(m reflect.Value, to reflect.Type) (reflect.Value, bool) {
+	from := m.Type().Key()
+	if !from.ConvertibleTo(to) || !to.ConvertibleTo(from) {
+		return m, false
+	}
+	// Go lets you convert an int to a string, but that gives you a rune, not
+	// the number as a string.
+	if (isNumeric(reflect.Zero(from)) == nil) != (isNumeric(reflect.Zero(to)) == nil) {
+		return m, false
+	}
+
+	converted := reflect.MakeMapWithSize(reflect.MapOf(to, m.Type().Elem()), m.Len())
+	for _, k := range m.MapKeys() {
+		ck := k.Convert(to)
+		if ck.Convert(from).Interface() != k.Interface() {
+			continue
+		}
+		converted.SetMapIndex(ck, m.MapIndex(k))
+	}
+
+	return converted, true
+}
+
+// sortedKeys returns the union of the keys of the two maps, sorted by their
+// string representation. We sort the keys so that failures are always
+// reported in the same order.
+func sortedKeys(actual, expect reflect.Value) []reflect.Value {
+	keys := actual.MapKeys()
+	for _, k := range expect.MapKeys() {
+		if !actual.MapIndex(k).IsValid() {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return fmt.Sprintf("%v", keys[i]) < fmt.Sprintf("%v", keys[j])
+	})
+
+	return keys
+}
+
+func (de *deepEqualer) compareStructs(actual, expect reflect.Value) bool {
+	actual = addressable(actual)
+	expect = addressable(expect)
+
+	equal := true
+	for i := 0; i < actual.NumField(); i++ {
+		f := actual.Type().Field(i)
+		de.pushPath("." + f.Name)
+		if de.opts.ignoresField(f) {
+			de.skip()
+		} else if !de.compare(field(actual, i), field(expect, i)) {
+			equal = false
+		}
+		de.popPath()
+
+		if !equal && !de.record {
+			return false
+		}
+	}
+
+	return equal
+}
+
+func (de *deepEqualer) comparePointers(actual, expect reflect.Value) bool {
+	if actual.Pointer() == expect.Pointer() {
+		return true
+	}
+	if actual.IsNil() || expect.IsNil() {
+		return de.fail(actual, expect, inValue, "")
+	}
+
+	if de.hasVisited(actual, expect) {
+		return true
+	}
+
+	data := "*"
+	cycle, leave := de.enter(actual)
+	defer leave()
+	if cycle != "" {
+		data = cycle
+	}
+
+	de.pushPath(data)
+	defer de.popPath()
+
+	return de.compare(actual.Elem(), expect.Elem())
+}
+
+func (de *deepEqualer) fail(actual, expect reflect.Value, where failure, description string) bool {
+	if !de.record {
+		return false
+	}
+
+	de.d.AddResult(result{
+		actual:      newValue(interfaceOrNil(actual)),
+		expect:      newValue(interfaceOrNil(expect)),
+		op:          de.op,
+		pass:        false,
+		where:       where,
+		description: description,
+	})
+
+	return false
+}
+
+func unwrapInterface(v reflect.Value) reflect.Value {
+	if v.IsValid() && v.Kind() == reflect.Interface {
+		return v.Elem()
+	}
+	return v
+}
+
+func interfaceOrNil(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func nilness(v reflect.Value) string {
+	if v.IsNil() {
+		return "a nil"
+	}
+	return "a non-nil"
+}
+
+// addressable returns an addressable copy of the given value, unless it is
+// already addressable. We need an addressable struct in order to read its
+// private fields.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	v2 := reflect.New(v.Type()).Elem()
+	v2.Set(v)
+	return v2
+}
+
+// field returns the i'th field of the given struct, which must be
+// addressable. The reflect package won't let us call Interface() on a value
+// from a private field, so we use the same unsafe hack as
+// StructTester.Field() to get a usable value.
+func field(v reflect.Value, i int) reflect.Value {
+	f := v.Field(i)
+	if f.CanInterface() {
+		return f
+	}
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
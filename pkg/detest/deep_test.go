@@ -0,0 +1,409 @@
+package detest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeepEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"Passing test", deepPassingTest},
+		{"Failing test in nested value", deepFailingNestedTest},
+		{"Multiple differences", deepMultipleDifferences},
+		{"Slices of different lengths", deepSliceLengthsDiffer},
+		{"Map keys differ", deepMapKeysDiffer},
+		{"Pointers are followed", deepPointersAreFollowed},
+		{"Private fields are compared", deepPrivateFieldsAreCompared},
+		{"Nil slice is not equal to empty slice", deepNilSliceIsNotEmptySlice},
+		{"Interface values of different types", deepInterfaceValuesOfDifferentTypes},
+		{"Identical cycles are equal", deepIdenticalCyclesAreEqual},
+		{"Subslices sharing a backing array are compared", deepSubslicesSharingBackingArray},
+		{"Different cycles are marked in the path", deepDifferentCyclesAreMarked},
+		{"IsNot passes with nested values", deepIsNotPassesWithNestedValues},
+		{"IsNot fails with nested values", deepIsNotFailsWithNestedValues},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+type order struct {
+	ID    int
+	Items map[string]int
+}
+
+func deepPassingTest(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]order{{ID: 1, Items: map[string]int{"sku": 2}}},
+		[]order{{ID: 1, Items: map[string]int{"sku": 2}}},
+		"deep equality",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: deep equality\n")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+}
+
+func deepFailingNestedTest(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]order{
+			{ID: 1, Items: map[string]int{"sku": 2}},
+			{ID: 2, Items: map[string]int{"sku": 3}},
+		},
+		[]order{
+			{ID: 1, Items: map[string]int{"sku": 2}},
+			{ID: 2, Items: map[string]int{"sku": 4}},
+		},
+		"deep equality",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 1, "one state was recorded")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	assert.Equal(
+		t,
+		&result{
+			actual: &value{value: 3, desc: "int"},
+			expect: &value{value: 4, desc: "int"},
+			op:     "==",
+			pass:   false,
+			path: []Path{
+				{
+					data:   "[]order",
+					callee: "detest.(*D).Equal",
+					caller: "detest.(*DetestRecorder).Is",
+				},
+				{
+					data:   "[1]",
+					callee: "detest.(*D).Equal",
+					caller: "detest.(*DetestRecorder).Is",
+				},
+				{
+					data:   ".Items",
+					callee: "detest.(*D).Equal",
+					caller: "detest.(*DetestRecorder).Is",
+				},
+				{
+					data:   "[sku]",
+					callee: "detest.(*D).Equal",
+					caller: "detest.(*DetestRecorder).Is",
+				},
+			},
+			where:       inValue,
+			description: "",
+		},
+		r.record[0].output[0].result,
+		"got the expected result",
+	)
+}
+
+func deepMultipleDifferences(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		order{ID: 1, Items: map[string]int{"a": 1, "b": 2}},
+		order{ID: 2, Items: map[string]int{"a": 1, "b": 3}},
+		"deep equality",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"order", ".ID"},
+			},
+			{
+				pass:     false,
+				dataPath: []string{"order", ".Items", "[b]"},
+			},
+		},
+		"got expected results",
+	)
+}
+
+func deepSliceLengthsDiffer(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]int{1, 2, 3},
+		[]int{1, 3},
+		"deep equality",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[]int"},
+			},
+			{
+				pass:     false,
+				dataPath: []string{"[]int", "[1]"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(t, inDataStructure, r.record[0].output[0].result.where, "first failure is in the data structure")
+	assert.Equal(
+		t,
+		"The actual slice has 3 elements but the expected slice has 2",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func deepMapKeysDiffer(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string]int{"a": 1, "b": 2},
+		map[string]int{"a": 1, "c": 2},
+		"deep equality",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"map[string]int", "[b]"},
+			},
+			{
+				pass:     false,
+				dataPath: []string{"map[string]int", "[c]"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"The actual map contains a key (b) that is not in the expected map",
+		r.record[0].output[0].result.description,
+		"got expected description for extra key",
+	)
+	assert.Equal(
+		t,
+		"The actual map does not contain the expected key c",
+		r.record[0].output[1].result.description,
+		"got expected description for missing key",
+	)
+}
+
+func deepPointersAreFollowed(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	x, y := 1, 2
+	r.Is(
+		[]*int{&x, &x},
+		[]*int{&x, &y},
+		"deep equality",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[]*int", "[1]", "*"},
+			},
+		},
+		"got expected results",
+	)
+}
+
+type private struct {
+	name string
+	tags []string
+}
+
+func deepPrivateFieldsAreCompared(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		private{name: "x", tags: []string{"a", "b"}},
+		private{name: "x", tags: []string{"a", "c"}},
+		"deep equality",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"private", ".tags", "[1]"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(t, "b", r.record[0].output[0].result.actual.value, "got the private value as the actual value")
+}
+
+func deepNilSliceIsNotEmptySlice(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string][]int{"a": {}},
+		map[string][]int{"a": nil},
+		"deep equality",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"map[string][]int", "[a]"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Expected a nil slice but got a non-nil slice",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func deepInterfaceValuesOfDifferentTypes(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]interface{}{1, "foo"},
+		[]interface{}{1, 42},
+		"deep equality",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[]interface {}", "[1]"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(t, inType, r.record[0].output[0].result.where, "failure is in the type")
+}
+
+func deepIsNotPassesWithNestedValues(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.IsNot(
+		[]order{{ID: 1, Items: map[string]int{"sku": 2}}},
+		[]order{{ID: 1, Items: map[string]int{"sku": 3}}},
+		"deep inequality",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: deep inequality\n")
+}
+
+func deepIsNotFailsWithNestedValues(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.IsNot(
+		[]order{{ID: 1, Items: map[string]int{"sku": 2}}},
+		[]order{{ID: 1, Items: map[string]int{"sku": 2}}},
+		"deep inequality",
+	)
+	mockT.AssertCalled(t, "Fail")
+}
+
+type node struct {
+	Val  int
+	Next *node
+}
+
+func ring(vals ...int) *node {
+	nodes := make([]*node, len(vals))
+	for i, v := range vals {
+		nodes[i] = &node{Val: v}
+	}
+	for i := range nodes {
+		nodes[i].Next = nodes[(i+1)%len(nodes)]
+	}
+	return nodes[0]
+}
+
+func deepIdenticalCyclesAreEqual(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(ring(1, 2, 3), ring(1, 2, 3), "rings are equal")
+	d.ValueIs(ring(1, 2, 3), ring(1, 2, 3), "rings are equal by value")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: rings are equal\n")
+}
+
+func deepDifferentCyclesAreMarked(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(ring(1, 2), ring(1, 2, 1), "rings are not equal")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 3, "record has state with three output items")
+	AssertResultsAre(
+		t,
+		r.record[0].output[:1],
+		[]resultExpect{
+			{
+				pass: false,
+				dataPath: []string{
+					"*node", "*", ".Next", "*", ".Next", "<cycle to *node>",
+					".Next", "<cycle to .Next>", ".Val",
+				},
+			},
+		},
+		"got expected results",
+	)
+}
+
+func deepSubslicesSharingBackingArray(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	a := []int{1, 2, 3}
+	e := []int{1, 2, 4}
+	r.Is(
+		[][]int{a[:2], a[:3]},
+		[][]int{e[:2], e[:3]},
+		"deep equality",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[][]int", "[1]", "[2]"},
+			},
+		},
+		"got expected results",
+	)
+}
@@ -71,13 +71,19 @@ type D struct {
 	callerPackageRoot string
 	state             *state
 	output            StringWriter
+	equality          *equalityRegistry
+	autoDeref         bool
 }
 
 var ourPackages = map[string]bool{}
 
+// This is the package path of detest itself.
+var detestPackage string
+
 // nolint: gochecknoinits
 func init() {
-	ourPackages[packageFromFrame(findFrame(0))] = true
+	detestPackage = packageFromFrame(findFrame(0))
+	ourPackages[detestPackage] = true
 }
 
 // RegisterPackage adds the caller's package to the list of "internal"
@@ -163,8 +169,12 @@ var funcNameRE = regexp.MustCompile(`^.+/`)
 // the caller's line and file is replaced with a function name so that we
 // don't show (unhelpful) information about the detest internals when
 // displaying the path.
+//
+// Frames from the generic typed wrappers in this package, like `detest.IsT`,
+// are skipped entirely, so that the path looks the same as it would if the
+// untyped method had been called directly.
 func (d *D) NewPath(data string, skip int, function string) Path {
-	pc := make([]uintptr, 2)
+	pc := make([]uintptr, 10)
 	// The hard-coded "2" is here because we want to skip this frame and the
 	// frame of the caller. We're interested in the frames before that.
 	n := runtime.Callers(2+skip, pc)
@@ -172,27 +182,45 @@ func (d *D) NewPath(data string, skip int, function string) Path {
 		return Path{data: data}
 	}
 
-	frames := runtime.CallersFrames(pc)
-	frame, more := frames.Next()
+	found := []runtime.Frame{}
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		if !isTypedWrapper(frame) {
+			found = append(found, frame)
+		}
+		if len(found) == 2 || !more {
+			break
+		}
+	}
 
-	var callee = calleeFromFrame(frame, function)
+	if len(found) == 0 {
+		return Path{data: data}
+	}
 
-	if !more {
+	var callee = calleeFromFrame(found[0], function)
+
+	if len(found) == 1 {
 		return Path{
 			data:   data,
 			callee: funcNameRE.ReplaceAllLiteralString(callee, ""),
 		}
 	}
 
-	frame, _ = frames.Next()
-
 	return Path{
 		data:   data,
 		callee: funcNameRE.ReplaceAllLiteralString(callee, ""),
-		caller: d.callerFromFrame(frame),
+		caller: d.callerFromFrame(found[1]),
 	}
 }
 
+// isTypedWrapper returns true if the frame is from one of the generic
+// functions or methods in this package. These are all thin wrappers around
+// the untyped API.
+func isTypedWrapper(frame runtime.Frame) bool {
+	return strings.Contains(frame.Function, "[...]") && packageFromFrame(frame) == detestPackage
+}
+
 func calleeFromFrame(frame runtime.Frame, function string) string {
 	if function != "" {
 		return function
@@ -257,6 +285,27 @@ func (d *D) AddWarning(w string) {
 	d.state.output = append(d.state.output, outputItem{warning: w})
 }
 
+// evaluate runs the comparer without leaving any of its output behind. It
+// returns whether the comparer passed along with the output it produced, so
+// the caller can decide whether to keep that output.
+func (d *D) evaluate(c Comparer) (bool, []outputItem) {
+	start := len(d.state.output)
+	c.Compare(d)
+
+	output := make([]outputItem, len(d.state.output)-start)
+	copy(output, d.state.output[start:])
+	d.state.output = d.state.output[:start]
+
+	pass := true
+	for _, o := range output {
+		if o.result != nil && !o.result.pass {
+			pass = false
+		}
+	}
+
+	return pass, output
+}
+
 func (d *D) lastResultIsNonValueError() bool {
 	if len(d.state.output) == 0 {
 		return false
@@ -0,0 +1,248 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/houseabsolute/detest/pkg/detest/internal/ansi"
+)
+
+// The number of unchanged lines shown around each change in a diff.
+const diffContext = 3
+
+// If two strings need more than this many line insertions and deletions to
+// get from one to the other, we give up on finding the smallest diff. The
+// memory used to find a diff grows with the square of the number of edits.
+const maxDiffEdits = 1000
+
+type diffOp byte
+
+const (
+	diffEqual  diffOp = ' '
+	diffDelete diffOp = '-'
+	diffInsert diffOp = '+'
+)
+
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// unifiedDiff returns a unified diff going from the actual value to the
+// expected value when both are multi-line strings or byte slices and the
+// result is from an equality comparison. Otherwise it returns an empty
+// string.
+func (r result) unifiedDiff(s ansi.Scheme) string {
+	if r.where != inValue || !r.isEquality() || !r.showActual() || !r.showExpect() {
+		return ""
+	}
+
+	got, ok := multiLineString(r.actual.value)
+	if !ok {
+		return ""
+	}
+	expect, ok := multiLineString(r.expect.value)
+	if !ok {
+		return ""
+	}
+	if !strings.Contains(got, "\n") && !strings.Contains(expect, "\n") {
+		return ""
+	}
+
+	return formatDiff(diffLines(strings.Split(got, "\n"), strings.Split(expect, "\n")), s)
+}
+
+func multiLineString(val interface{}) (string, bool) {
+	v := reflect.ValueOf(val)
+	switch {
+	case !v.IsValid():
+		return "", false
+	case v.Kind() == reflect.String:
+		return v.String(), true
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return string(v.Bytes()), true
+	}
+	return "", false
+}
+
+// diffLines finds the shortest edit script from a to b using Myers' diff
+// algorithm. Lines only in a are deletions and lines only in b are
+// insertions.
+func diffLines(a, b []string) []diffLine {
+	// Trimming the common prefix and suffix first makes the common case of
+	// a few changes in a long string much cheaper.
+	var prefix, suffix []diffLine
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		prefix = append(prefix, diffLine{diffEqual, a[0]})
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		suffix = append([]diffLine{{diffEqual, a[len(a)-1]}}, suffix...)
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	lines := append(prefix, myersDiff(a, b)...)
+	return append(lines, suffix...)
+}
+
+func myersDiff(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	total := n + m
+	if n == 0 || m == 0 {
+		return replaceAll(a, b)
+	}
+
+	// v[off+k] is the furthest x reached on diagonal k. Each trace entry is a
+	// copy of v[off-d-1:off+d+2] from before we look for paths with d edits.
+	off := total + 1
+	v := make([]int, 2*total+3)
+	var trace [][]int
+
+	for d := 0; d <= total; d++ {
+		if d > maxDiffEdits {
+			return replaceAll(a, b)
+		}
+
+		trace = append(trace, append([]int{}, v[off-d-1:off+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+
+	// We always find a path with at most n+m edits.
+	panic("Should never get here - no diff found")
+}
+
+func backtrack(a, b []string, trace [][]int) []diffLine {
+	x, y := len(a), len(b)
+	var reversed []diffLine
+	for d := len(trace) - 1; d >= 0; d-- {
+		// The snapshot for d starts at diagonal -d-1.
+		v := func(k int) int { return trace[d][k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v(k-1) < v(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, diffLine{diffEqual, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, diffLine{diffInsert, b[y-1]})
+			} else {
+				reversed = append(reversed, diffLine{diffDelete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	lines := make([]diffLine, len(reversed))
+	for i, l := range reversed {
+		lines[len(reversed)-1-i] = l
+	}
+	return lines
+}
+
+func replaceAll(a, b []string) []diffLine {
+	var lines []diffLine
+	for _, l := range a {
+		lines = append(lines, diffLine{diffDelete, l})
+	}
+	for _, l := range b {
+		lines = append(lines, diffLine{diffInsert, l})
+	}
+	return lines
+}
+
+// formatDiff groups the changed lines into hunks with some context and
+// renders them in unified diff format.
+func formatDiff(lines []diffLine, s ansi.Scheme) string {
+	var b strings.Builder
+	b.WriteString(s.Incorrect("--- GOT") + "\n")
+	b.WriteString(s.Correct("+++ EXPECT") + "\n")
+
+	i := 0
+	for i < len(lines) {
+		if lines[i].op == diffEqual {
+			i++
+			continue
+		}
+
+		// Find the end of this hunk. A hunk continues as long as two changes
+		// are separated by no more than twice the context.
+		start := max(0, i-diffContext)
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].op != diffEqual {
+				end = j
+			} else if j-end > 2*diffContext {
+				break
+			}
+		}
+		end = min(len(lines), end+diffContext+1)
+
+		writeHunk(&b, lines, start, end, s)
+		i = end
+	}
+
+	return b.String()
+}
+
+func writeHunk(b *strings.Builder, lines []diffLine, start, end int, s ansi.Scheme) {
+	// Line numbers in a unified diff start at 1.
+	gotStart, expectStart := 1, 1
+	for _, l := range lines[:start] {
+		if l.op != diffInsert {
+			gotStart++
+		}
+		if l.op != diffDelete {
+			expectStart++
+		}
+	}
+
+	var gotLen, expectLen int
+	for _, l := range lines[start:end] {
+		if l.op != diffInsert {
+			gotLen++
+		}
+		if l.op != diffDelete {
+			expectLen++
+		}
+	}
+
+	b.WriteString(s.Strong(fmt.Sprintf("@@ -%d,%d +%d,%d @@", gotStart, gotLen, expectStart, expectLen)) + "\n")
+	for _, l := range lines[start:end] {
+		line := string(l.op) + l.text
+		switch l.op {
+		case diffDelete:
+			line = s.Incorrect(line)
+		case diffInsert:
+			line = s.Correct(line)
+		}
+		b.WriteString(line + "\n")
+	}
+}
@@ -0,0 +1,173 @@
+package detest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/houseabsolute/detest/pkg/detest/internal/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("diffLines", diffLinesTests)
+	t.Run("formatDiff", diffFormatTests)
+	t.Run("Too many edits", diffTooManyEdits)
+	t.Run("Failure output", diffFailureOutput)
+	t.Run("Single line strings", diffSingleLineStrings)
+	t.Run("Not used for comparers other than equality", diffNotUsedForOtherComparers)
+}
+
+func diffLinesTests(t *testing.T) {
+	tests := []struct {
+		name   string
+		a      string
+		b      string
+		expect string
+	}{
+		{"equal", "a\nb", "a\nb", " a| b"},
+		{"changed line", "a\nb\nc", "a\nx\nc", " a|-b|+x| c"},
+		{"inserted line", "a\nc", "a\nb\nc", " a|+b| c"},
+		{"deleted line", "a\nb\nc", "a\nc", " a|-b| c"},
+		{"all different", "a\nb", "c\nd", "-a|-b|+c|+d"},
+		{"moved line", "a\nb\nc\nd", "b\nc\na\nd", "-a| b| c|+a| d"},
+		{"empty and non-empty", "", "a\nb", "-|+a|+b"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			lines := diffLines(strings.Split(test.a, "\n"), strings.Split(test.b, "\n"))
+			var got []string
+			for _, l := range lines {
+				got = append(got, string(l.op)+l.text)
+			}
+			assert.Equal(t, test.expect, strings.Join(got, "|"), "got expected diff")
+		})
+	}
+}
+
+func numberedLines(n int, changed map[int]string) string {
+	var lines []string
+	for i := 1; i <= n; i++ {
+		if c, ok := changed[i]; ok {
+			lines = append(lines, c)
+		} else {
+			lines = append(lines, fmt.Sprintf("line %d", i))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func diffFormatTests(t *testing.T) {
+	got := numberedLines(20, map[int]string{2: "changed 2", 4: "changed 4", 16: "changed 16"})
+	expect := numberedLines(20, nil)
+	diff := formatDiff(diffLines(strings.Split(got, "\n"), strings.Split(expect, "\n")), ansi.DefaultScheme)
+	assert.Equal(
+		t,
+		strings.Join([]string{
+			"--- GOT",
+			"+++ EXPECT",
+			"@@ -1,7 +1,7 @@",
+			" line 1",
+			"-changed 2",
+			"+line 2",
+			" line 3",
+			"-changed 4",
+			"+line 4",
+			" line 5",
+			" line 6",
+			" line 7",
+			"@@ -13,7 +13,7 @@",
+			" line 13",
+			" line 14",
+			" line 15",
+			"-changed 16",
+			"+line 16",
+			" line 17",
+			" line 18",
+			" line 19",
+			"",
+		}, "\n"),
+		ansi.Strip(diff),
+		"got expected unified diff",
+	)
+}
+
+func diffTooManyEdits(t *testing.T) {
+	var a, b []string
+	for i := 0; i <= maxDiffEdits; i++ {
+		a = append(a, fmt.Sprintf("a%d", i))
+		b = append(b, fmt.Sprintf("b%d", i))
+	}
+	lines := diffLines(a, b)
+	assert.Len(t, lines, len(a)+len(b), "got one line for each deletion and insertion")
+	assert.Equal(t, diffLine{diffDelete, "a0"}, lines[0], "first line is a deletion")
+	assert.Equal(t, diffLine{diffInsert, "b0"}, lines[len(a)], "insertions follow deletions")
+}
+
+func diffFailureOutput(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		"first\nsecond\nthird\n",
+		"first\n2nd\nthird\n",
+		"multi-line strings",
+	)
+	mockT.AssertCalled(t, "Fail")
+
+	call := mockT.FindCall("WriteString")
+	if !assert.NotNil(t, call, "WriteString was called") {
+		return
+	}
+	output := ansi.Strip(call.Args[0].(string))
+	assert.Contains(t, output, "<see diff below>", "table refers to the diff")
+	assert.NotContains(t, output, "first\nsecond", "table does not contain the raw string")
+	assert.Contains(
+		t,
+		output,
+		strings.Join([]string{
+			"--- GOT",
+			"+++ EXPECT",
+			"@@ -1,4 +1,4 @@",
+			" first",
+			"-second",
+			"+2nd",
+			" third",
+			" ",
+			"",
+		}, "\n"),
+		"output contains a unified diff",
+	)
+}
+
+func diffSingleLineStrings(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is("foo", "bar", "single-line strings")
+	mockT.AssertCalled(t, "Fail")
+
+	call := mockT.FindCall("WriteString")
+	if !assert.NotNil(t, call, "WriteString was called") {
+		return
+	}
+	output := ansi.Strip(call.Args[0].(string))
+	assert.NotContains(t, output, "--- GOT", "no diff for single-line strings")
+	assert.Contains(t, output, "foo", "table contains the actual string")
+}
+
+func diffNotUsedForOtherComparers(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes("first\nsecond\nthird\n", d.Contains("fourth\n"), "substring")
+	mockT.AssertCalled(t, "Fail")
+
+	call := mockT.FindCall("WriteString")
+	if !assert.NotNil(t, call, "WriteString was called") {
+		return
+	}
+	output := ansi.Strip(call.Args[0].(string))
+	assert.NotContains(t, output, "<see diff below>", "table does not refer to a diff")
+	assert.NotContains(t, output, "--- GOT", "output does not contain a diff")
+	assert.Contains(t, output, "second", "table contains the actual string")
+}
@@ -0,0 +1,220 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// EqualityFunc is a function which decides whether two values of the same
+// type are equal. It is called with the actual value first and the expected
+// value second.
+type EqualityFunc func(actual, expect interface{}) bool
+
+// ComparerFactory is a function which takes an expected value and returns a
+// `Comparer` that checks the actual value against it.
+type ComparerFactory func(expect interface{}) Comparer
+
+type equality struct {
+	f       EqualityFunc
+	factory ComparerFactory
+}
+
+// equalityRegistry maps types to custom equality checks. It also tracks the
+// types for which we should not automatically use an `Equal` method.
+type equalityRegistry struct {
+	mu           sync.RWMutex
+	types        map[reflect.Type]equality
+	ignoreMethod map[reflect.Type]bool
+}
+
+func newEqualityRegistry() *equalityRegistry {
+	return &equalityRegistry{
+		types:        map[reflect.Type]equality{},
+		ignoreMethod: map[reflect.Type]bool{},
+	}
+}
+
+var defaultRegistry = newEqualityRegistry()
+
+// RegisterEquality registers a function to use when comparing two values of
+// the given type for equality. This registration applies to every `*D`. Use
+// `d.RegisterEquality` to register a function for a single `*D`.
+//
+// The registered function is used by `d.Is`, `d.Equal`, `d.ValueIs`, and
+// every comparison of a value inside a slice, map, or struct.
+func RegisterEquality(ty reflect.Type, f EqualityFunc) {
+	defaultRegistry.register(ty, equality{f: f})
+}
+
+// RegisterComparer registers a `ComparerFactory` to use when comparing two
+// values of the given type for equality. The factory is called with the
+// expected value, and the `Comparer` it returns is used to check the actual
+// value. This registration applies to every `*D`. Use `d.RegisterComparer` to
+// register a factory for a single `*D`.
+func RegisterComparer(ty reflect.Type, factory ComparerFactory) {
+	defaultRegistry.register(ty, equality{factory: factory})
+}
+
+// IgnoreEqualMethod tells detest not to use the `Equal` method of the given
+// type when comparing two values of that type. By default, any type with a
+// method like `Equal(other T) bool` is compared by calling that method. This
+// applies to every `*D`. Use `d.IgnoreEqualMethod` to do this for a single
+// `*D`.
+func IgnoreEqualMethod(ty reflect.Type) {
+	defaultRegistry.ignore(ty)
+}
+
+// RegisterEquality registers a function to use when comparing two values of
+// the given type for equality with this `*D`. Anything registered with a `*D`
+// takes precedence over registrations made with the package-level
+// `detest.RegisterEquality` and `detest.RegisterComparer` functions.
+func (d *D) RegisterEquality(ty reflect.Type, f EqualityFunc) {
+	d.registry().register(ty, equality{f: f})
+}
+
+// RegisterComparer registers a `ComparerFactory` to use when comparing two
+// values of the given type for equality with this `*D`. Anything registered
+// with a `*D` takes precedence over registrations made with the package-level
+// `detest.RegisterEquality` and `detest.RegisterComparer` functions.
+func (d *D) RegisterComparer(ty reflect.Type, factory ComparerFactory) {
+	d.registry().register(ty, equality{factory: factory})
+}
+
+// IgnoreEqualMethod tells this `*D` not to use the `Equal` method of the
+// given type when comparing two values of that type.
+func (d *D) IgnoreEqualMethod(ty reflect.Type) {
+	d.registry().ignore(ty)
+}
+
+func (d *D) registry() *equalityRegistry {
+	if d.equality == nil {
+		d.equality = newEqualityRegistry()
+	}
+	return d.equality
+}
+
+func (er *equalityRegistry) register(ty reflect.Type, e equality) {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	er.types[ty] = e
+}
+
+func (er *equalityRegistry) ignore(ty reflect.Type) {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	er.ignoreMethod[ty] = true
+}
+
+func (er *equalityRegistry) lookup(ty reflect.Type) (equality, bool) {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+	e, ok := er.types[ty]
+	return e, ok
+}
+
+func (er *equalityRegistry) ignores(ty reflect.Type) bool {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+	return er.ignoreMethod[ty]
+}
+
+// equalityFor returns the custom equality check for the given type, looking
+// first at this `*D` and then at the package-level registry.
+func (d *D) equalityFor(ty reflect.Type) (equality, bool) {
+	if d.equality != nil {
+		if e, ok := d.equality.lookup(ty); ok {
+			return e, true
+		}
+	}
+	return defaultRegistry.lookup(ty)
+}
+
+// equalMethodFor returns the type's `Equal` method if it has one that takes a
+// single value of the same type and returns a bool, unless we've been told to
+// ignore that type's method.
+func (d *D) equalMethodFor(ty reflect.Type) (reflect.Method, bool) {
+	// The methods of an interface type don't have a receiver, so we cannot
+	// call them the same way. We'll look at the value inside the interface
+	// instead.
+	if ty.Kind() == reflect.Interface {
+		return reflect.Method{}, false
+	}
+
+	if (d.equality != nil && d.equality.ignores(ty)) || defaultRegistry.ignores(ty) {
+		return reflect.Method{}, false
+	}
+
+	m, ok := ty.MethodByName("Equal")
+	if !ok {
+		return reflect.Method{}, false
+	}
+
+	// The method's type includes the receiver as its first argument.
+	mt := m.Type
+	if mt.NumIn() != 2 || mt.In(1) != ty || mt.NumOut() != 1 || mt.Out(0).Kind() != reflect.Bool {
+		return reflect.Method{}, false
+	}
+
+	return m, true
+}
+
+// compareWithRegistry checks whether there's a custom equality check for the
+// type of the two values. If there is, it's used to compare them and this
+// returns true along with the result of the comparison. Otherwise the first
+// return value is false.
+func (de *deepEqualer) compareWithRegistry(actual, expect reflect.Value) (bool, bool) {
+	ty := actual.Type()
+	if e, ok := de.d.equalityFor(ty); ok {
+		if e.factory != nil {
+			return true, de.compareWithComparer(actual, e.factory(expect.Interface()))
+		}
+
+		if e.f(actual.Interface(), expect.Interface()) {
+			return true, true
+		}
+		return true, de.fail(
+			actual, expect, inValue,
+			fmt.Sprintf("The equality func registered for %s says these values are not equal", describeType(ty)),
+		)
+	}
+
+	m, ok := de.d.equalMethodFor(ty)
+	if !ok {
+		return false, false
+	}
+
+	// Calling a method on a nil pointer is likely to panic, so we'll just
+	// fall back to our own comparison.
+	if ty.Kind() == reflect.Ptr && (actual.IsNil() || expect.IsNil()) {
+		return false, false
+	}
+
+	if m.Func.Call([]reflect.Value{actual, expect})[0].Bool() {
+		return true, true
+	}
+	return true, de.fail(
+		actual, expect, inValue,
+		fmt.Sprintf("%s.Equal() says these values are not equal", describeType(ty)),
+	)
+}
+
+func (de *deepEqualer) compareWithComparer(actual reflect.Value, c Comparer) bool {
+	de.d.PushActual(actual.Interface())
+	defer de.d.PopActual()
+
+	pass, output := de.d.evaluate(c)
+	if !de.record {
+		return pass
+	}
+
+	// If the comparer passed we only keep its warnings. The caller will add a
+	// passing result for the whole comparison.
+	for _, o := range output {
+		if !pass || o.result == nil {
+			de.d.state.output = append(de.d.state.output, o)
+		}
+	}
+
+	return pass
+}
@@ -0,0 +1,243 @@
+package detest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEquality(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"Equal method is used", equalityEqualMethodIsUsed},
+		{"Equal method is used for nested values", equalityEqualMethodIsUsedForNestedValues},
+		{"Equal method failure", equalityEqualMethodFails},
+		{"Equal method can be ignored", equalityEqualMethodCanBeIgnored},
+		{"Func registered with D", equalityFuncRegisteredWithD},
+		{"Func registered with D fails", equalityFuncRegisteredWithDFails},
+		{"Func registered with package", equalityFuncRegisteredWithPackage},
+		{"D registration takes precedence", equalityDRegistrationTakesPrecedence},
+		{"Comparer factory", equalityComparerFactory},
+		{"Comparer factory fails", equalityComparerFactoryFails},
+		{"Slice tester uses registry", equalitySliceTesterUsesRegistry},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+func sameTimeInTwoZones() (time.Time, time.Time) {
+	t1 := time.Date(2021, 3, 27, 12, 0, 0, 0, time.UTC)
+	return t1, t1.In(time.FixedZone("UTC+1", 3600))
+}
+
+func equalityEqualMethodIsUsed(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	t1, t2 := sameTimeInTwoZones()
+	d.Is(t1, t2, "times are equal")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: times are equal\n")
+}
+
+type event struct {
+	Name string
+	At   time.Time
+}
+
+func equalityEqualMethodIsUsedForNestedValues(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	t1, t2 := sameTimeInTwoZones()
+	d.Is(
+		map[string][]event{"x": {{Name: "foo", At: t1}}},
+		map[string][]event{"x": {{Name: "foo", At: t2}}},
+		"events are equal",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: events are equal\n")
+}
+
+func equalityEqualMethodFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	t1, _ := sameTimeInTwoZones()
+	r.Is(
+		[]event{{Name: "foo", At: t1}},
+		[]event{{Name: "foo", At: t1.Add(time.Second)}},
+		"events are not equal",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[]event", "[0]", ".At"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Time.Equal() says these values are not equal",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func equalityEqualMethodCanBeIgnored(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.IgnoreEqualMethod(reflect.TypeOf(time.Time{}))
+	t1, t2 := sameTimeInTwoZones()
+	d.Is(t1, t2, "times are not equal")
+	mockT.AssertCalled(t, "Fail")
+}
+
+type caseless string
+
+func caselessEquality(actual, expect interface{}) bool {
+	return strings.EqualFold(string(actual.(caseless)), string(expect.(caseless)))
+}
+
+func equalityFuncRegisteredWithD(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.RegisterEquality(reflect.TypeOf(caseless("")), caselessEquality)
+	d.Is([]caseless{"Foo", "BAR"}, []caseless{"foo", "bar"}, "caseless values are equal")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: caseless values are equal\n")
+}
+
+func equalityFuncRegisteredWithDFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.RegisterEquality(reflect.TypeOf(caseless("")), caselessEquality)
+	r.Is([]caseless{"Foo", "BAR"}, []caseless{"foo", "baz"}, "caseless values are not equal")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[]caseless", "[1]"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"The equality func registered for caseless says these values are not equal",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+type packageCaseless string
+
+func equalityFuncRegisteredWithPackage(t *testing.T) {
+	RegisterEquality(
+		reflect.TypeOf(packageCaseless("")),
+		func(actual, expect interface{}) bool {
+			return strings.EqualFold(string(actual.(packageCaseless)), string(expect.(packageCaseless)))
+		},
+	)
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(packageCaseless("Foo"), packageCaseless("foo"), "caseless values are equal")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: caseless values are equal\n")
+}
+
+type precedence int
+
+func equalityDRegistrationTakesPrecedence(t *testing.T) {
+	RegisterEquality(
+		reflect.TypeOf(precedence(0)),
+		func(actual, expect interface{}) bool { return true },
+	)
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.RegisterEquality(
+		reflect.TypeOf(precedence(0)),
+		func(actual, expect interface{}) bool { return false },
+	)
+	d.Is(precedence(1), precedence(1), "D's func is used")
+	mockT.AssertCalled(t, "Fail")
+}
+
+type approxInt int
+
+func approxIntComparer(d *D) ComparerFactory {
+	return func(expect interface{}) Comparer {
+		f, err := d.NamedFunc(
+			func(actual approxInt) bool {
+				diff := actual - expect.(approxInt)
+				return diff >= -1 && diff <= 1
+			},
+			"approxInt",
+		)
+		if err != nil {
+			panic(err)
+		}
+		return f
+	}
+}
+
+func equalityComparerFactory(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.RegisterComparer(reflect.TypeOf(approxInt(0)), approxIntComparer(d))
+	r.Is([]approxInt{1, 5}, []approxInt{2, 4}, "values are close enough")
+	mockT.AssertNotCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+}
+
+func equalityComparerFactoryFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.RegisterComparer(reflect.TypeOf(approxInt(0)), approxIntComparer(d))
+	r.Is([]approxInt{1, 5}, []approxInt{2, 7}, "values are not close enough")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[]approxInt", "[1]", "approxInt"},
+			},
+		},
+		"got expected results",
+	)
+}
+
+func equalitySliceTesterUsesRegistry(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.RegisterEquality(reflect.TypeOf(caseless("")), caselessEquality)
+	d.Is(
+		[]caseless{"Foo"},
+		d.Slice(func(st *SliceTester) {
+			st.Idx(0, caseless("FOO"))
+			st.End()
+		}),
+		"slice value is equal",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+}
@@ -0,0 +1,313 @@
+package detest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ErrorIsComparer implements a check that an error wraps a target error.
+type ErrorIsComparer struct {
+	target error
+}
+
+// ErrorIs takes a target error and returns an ErrorIsComparer which passes if
+// `errors.Is` returns true for the actual error and the target. On failure,
+// the output shows every error in the actual error's chain.
+func (d *D) ErrorIs(target error) ErrorIsComparer {
+	return ErrorIsComparer{target}
+}
+
+// Compare checks whether the error in d.Actual() is or wraps the target error
+// passed to ErrorIs().
+func (eic ErrorIsComparer) Compare(d *D) {
+	path := d.NewPath(describeTypeOfActualValue(d.Actual()), 1, "detest.(*D).ErrorIs")
+	d.PushPath(path)
+	defer d.PopPath()
+
+	err, ok := errorFromActual(d, "ErrorIs")
+	if !ok {
+		return
+	}
+
+	result := result{
+		actual: newValue(err),
+		expect: newValue(eic.target),
+		op:     "errors.Is",
+		pass:   errors.Is(err, eic.target),
+	}
+	if result.pass {
+		d.AddResult(result)
+		return
+	}
+
+	result.where = inValue
+	result.description = "errors.Is() returned false for this error and for every error that it wraps"
+	addErrorChain(d, path, err, result)
+}
+
+// ErrorAsComparer implements a check that an error's chain contains an error
+// which can be assigned to a target.
+type ErrorAsComparer struct {
+	target interface{}
+	then   []Comparer
+}
+
+// ErrorAs takes a pointer to a target and returns an ErrorAsComparer which
+// passes if `errors.As` finds an error in the actual error's chain that can
+// be assigned to that target. The target must be a non-nil pointer to an
+// interface or to a type that implements `error`. Any comparers passed after
+// the target are run against the error that was found. On failure, the
+// output shows every error in the actual error's chain.
+func (d *D) ErrorAs(target interface{}, then ...Comparer) ErrorAsComparer {
+	return ErrorAsComparer{target, then}
+}
+
+// Compare checks whether the error in d.Actual() can be assigned to the target
+// passed to ErrorAs().
+func (eac ErrorAsComparer) Compare(d *D) {
+	path := d.NewPath(describeTypeOfActualValue(d.Actual()), 1, "detest.(*D).ErrorAs")
+	d.PushPath(path)
+	defer d.PopPath()
+
+	target := reflect.ValueOf(eac.target)
+	if !target.IsValid() || target.Kind() != reflect.Ptr || target.IsNil() ||
+		(target.Elem().Kind() != reflect.Interface && !target.Type().Elem().Implements(errorType)) {
+		d.AddResult(result{
+			actual: newValue(d.Actual()),
+			pass:   false,
+			where:  inUsage,
+			description: fmt.Sprintf(
+				"You passed %s to ErrorAs() but it needs a non-nil pointer to an interface or to a type that implements error",
+				articleize(describeTypeOfReflectValue(target)),
+			),
+		})
+		return
+	}
+
+	err, ok := errorFromActual(d, "ErrorAs")
+	if !ok {
+		return
+	}
+
+	targetType := target.Type().Elem()
+	result := result{
+		actual: newValue(err),
+		expect: &value{value: targetType.String(), desc: describeType(targetType)},
+		op:     "errors.As",
+		pass:   errors.As(err, eac.target),
+	}
+	if !result.pass {
+		result.where = inType
+		result.description = fmt.Sprintf(
+			"errors.As() could not assign this error or any error that it wraps to %s",
+			articleize(describeType(targetType)),
+		)
+		addErrorChain(d, path, err, result)
+		return
+	}
+	d.AddResult(result)
+
+	d.PushActual(target.Elem().Interface())
+	defer d.PopActual()
+
+	for _, c := range eac.then {
+		c.Compare(d)
+	}
+}
+
+// ErrorMessageComparer implements a check of an error's message.
+type ErrorMessageComparer struct {
+	expect interface{}
+}
+
+// ErrorMessage takes an expected string or a comparer, such as one returned
+// by `d.HasPrefix`, and returns an ErrorMessageComparer which checks the
+// string returned by the actual error's `Error()` method. On failure, the
+// output shows the message of every error in the actual error's chain.
+func (d *D) ErrorMessage(expect interface{}) ErrorMessageComparer {
+	return ErrorMessageComparer{expect}
+}
+
+// Compare checks the message of the error in d.Actual().
+func (emc ErrorMessageComparer) Compare(d *D) {
+	path := d.NewPath(describeTypeOfActualValue(d.Actual()), 1, "detest.(*D).ErrorMessage")
+	d.PushPath(path)
+	defer d.PopPath()
+
+	err, ok := errorFromActual(d, "ErrorMessage")
+	if !ok {
+		return
+	}
+
+	if err == nil {
+		d.AddResult(result{
+			actual:      newValue(d.Actual()),
+			pass:        false,
+			where:       inValue,
+			op:          "Error()",
+			description: "Called detest.ErrorMessage() but the value being tested is a nil error",
+		})
+		return
+	}
+
+	c, ok := emc.expect.(Comparer)
+	if !ok {
+		c = d.Equal(emc.expect)
+	}
+
+	d.PushPath(Path{data: "Error()", callee: path.callee, caller: path.caller})
+	d.PushActual(err.Error())
+	pass, output := d.evaluate(c)
+	d.PopActual()
+	d.PopPath()
+
+	d.state.output = append(d.state.output, output...)
+	if pass {
+		return
+	}
+
+	addErrorChain(d, path, err, result{
+		op:          "Error()",
+		where:       inValue,
+		description: "This is the chain of errors that produced the message",
+	})
+}
+
+// NoErrorComparer implements a check that a value is a nil error.
+type NoErrorComparer struct{}
+
+// NoError returns a NoErrorComparer which passes if the actual value is nil.
+// On failure, the output shows every error in the actual error's chain.
+func (d *D) NoError() NoErrorComparer {
+	return NoErrorComparer{}
+}
+
+// Compare checks that the value in d.Actual() is nil.
+func (nec NoErrorComparer) Compare(d *D) {
+	path := d.NewPath(describeTypeOfActualValue(d.Actual()), 1, "detest.(*D).NoError")
+	d.PushPath(path)
+	defer d.PopPath()
+
+	err, ok := errorFromActual(d, "NoError")
+	if !ok {
+		return
+	}
+
+	result := result{
+		actual: newValue(err),
+		op:     "== nil",
+		pass:   err == nil,
+	}
+	if result.pass {
+		d.AddResult(result)
+		return
+	}
+
+	result.where = inValue
+	result.description = "Expected no error but got one"
+	addErrorChain(d, path, err, result)
+}
+
+// AnErrorComparer implements a check that a value is a non-nil error.
+type AnErrorComparer struct{}
+
+// AnError returns an AnErrorComparer which passes if the actual value is a
+// non-nil error.
+func (d *D) AnError() AnErrorComparer {
+	return AnErrorComparer{}
+}
+
+// Compare checks that the value in d.Actual() is a non-nil error.
+func (aec AnErrorComparer) Compare(d *D) {
+	d.PushPath(d.NewPath(describeTypeOfActualValue(d.Actual()), 1, "detest.(*D).AnError"))
+	defer d.PopPath()
+
+	err, ok := errorFromActual(d, "AnError")
+	if !ok {
+		return
+	}
+
+	result := result{
+		actual: newValue(err),
+		op:     "!= nil",
+		pass:   err != nil,
+	}
+	if !result.pass {
+		result.where = inValue
+		result.description = "Expected an error but got nil"
+	}
+	d.AddResult(result)
+}
+
+// errorFromActual returns the error in d.Actual(), which may be nil. If the
+// actual value is not an error it adds a failure and returns false.
+func errorFromActual(d *D, called string) (error, bool) {
+	actual := d.Actual()
+	if actual == nil {
+		return nil, true
+	}
+
+	if err, ok := actual.(error); ok {
+		return err, true
+	}
+
+	d.AddResult(result{
+		actual: newValue(actual),
+		pass:   false,
+		where:  inType,
+		description: fmt.Sprintf(
+			"Called detest.%s() but the value being tested isn't an error, it's %s",
+			called,
+			articleize(describeTypeOfActualValue(actual)),
+		),
+	})
+	return nil, false
+}
+
+// addErrorChain adds a failure for the error and for each error that it
+// wraps. The first failure is the given result. The failures for wrapped
+// errors have the same op and expected value, with a path element like
+// `Unwrap()` or `Unwrap()[1]` for each layer of wrapping.
+func addErrorChain(d *D, path Path, err error, first result) {
+	first.actual = newValue(err)
+	first.pass = false
+	d.AddResult(first)
+
+	layer := result{
+		expect: first.expect,
+		op:     first.op,
+		pass:   false,
+		where:  first.where,
+	}
+
+	var walk func(err error)
+	walk = func(err error) {
+		var wrapped []error
+		var data []string
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for i, e := range u.Unwrap() {
+				wrapped = append(wrapped, e)
+				data = append(data, fmt.Sprintf("Unwrap()[%d]", i))
+			}
+		case interface{ Unwrap() error }:
+			wrapped = append(wrapped, u.Unwrap())
+			data = append(data, "Unwrap()")
+		}
+
+		for i, e := range wrapped {
+			if e == nil {
+				continue
+			}
+			d.PushPath(Path{data: data[i], callee: path.callee, caller: path.caller})
+			layer.actual = newValue(e)
+			d.AddResult(layer)
+			walk(e)
+			d.PopPath()
+		}
+	}
+	walk(err)
+}
@@ -0,0 +1,281 @@
+package detest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestSentinel = errors.New("sentinel")
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"ErrorIs passes", errorsErrorIsPasses},
+		{"ErrorIs renders the chain", errorsErrorIsChain},
+		{"ErrorIs renders joined errors", errorsErrorIsJoined},
+		{"ErrorAs passes", errorsErrorAsPasses},
+		{"ErrorAs fails", errorsErrorAsFails},
+		{"ErrorAs runs comparers on the match", errorsErrorAsComparers},
+		{"ErrorAs with a bad target", errorsErrorAsBadTarget},
+		{"ErrorMessage passes", errorsErrorMessagePasses},
+		{"ErrorMessage fails", errorsErrorMessageFails},
+		{"NoError and AnError pass", errorsNoErrorAndAnErrorPass},
+		{"NoError fails", errorsNoErrorFails},
+		{"AnError fails", errorsAnErrorFails},
+		{"Non-error value", errorsNonError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+func errorsErrorIsPasses(t *testing.T) {
+	wrapped := fmt.Errorf("outer: %w", errTestSentinel)
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(wrapped, d.ErrorIs(errTestSentinel), "wrapped")
+	d.Passes(errors.Join(errors.New("a"), wrapped), d.ErrorIs(errTestSentinel), "joined errors")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: wrapped\n")
+}
+
+func errorsErrorIsChain(t *testing.T) {
+	err := fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", errors.New("root")))
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(err, r.ErrorIs(errTestSentinel), "ErrorIs")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"*wrapError"}},
+			{pass: false, dataPath: []string{"*wrapError", "Unwrap()"}},
+			{pass: false, dataPath: []string{"*wrapError", "Unwrap()", "Unwrap()"}},
+		},
+		"got expected results",
+	)
+	output := r.record[0].output
+	assert.Equal(
+		t,
+		"errors.Is() returned false for this error and for every error that it wraps",
+		output[0].result.description,
+		"got expected description",
+	)
+	assert.Equal(t, "detest.(*D).ErrorIs", output[2].result.path[2].callee, "got expected callee for the chain")
+	assert.Equal(t, "root", output[2].result.actual.value.(error).Error(), "last result is the root error")
+	assert.Equal(t, errTestSentinel, output[2].result.expect.value, "chain results show the target")
+}
+
+func errorsErrorIsJoined(t *testing.T) {
+	err := fmt.Errorf("outer: %w", errors.Join(errors.New("a"), fmt.Errorf("b: %w", errors.New("c"))))
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(err, r.ErrorIs(errTestSentinel), "ErrorIs")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"*wrapError"}},
+			{pass: false, dataPath: []string{"*wrapError", "Unwrap()"}},
+			{pass: false, dataPath: []string{"*wrapError", "Unwrap()", "Unwrap()[0]"}},
+			{pass: false, dataPath: []string{"*wrapError", "Unwrap()", "Unwrap()[1]"}},
+			{pass: false, dataPath: []string{"*wrapError", "Unwrap()", "Unwrap()[1]", "Unwrap()"}},
+		},
+		"got expected results",
+	)
+}
+
+func errorsErrorAsPasses(t *testing.T) {
+	err := fmt.Errorf("open: %w", &fs.PathError{Op: "open", Path: "/x", Err: fs.ErrNotExist})
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	var target *fs.PathError
+	d.Passes(err, d.ErrorAs(&target), "ErrorAs")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: ErrorAs\n")
+}
+
+func errorsErrorAsFails(t *testing.T) {
+	err := fmt.Errorf("outer: %w", errTestSentinel)
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	var target *fs.PathError
+	r.Passes(err, r.ErrorAs(&target), "ErrorAs")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"*wrapError"}},
+			{pass: false, dataPath: []string{"*wrapError", "Unwrap()"}},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, inType, res.where, "failure is in the type")
+	assert.Equal(
+		t,
+		"errors.As() could not assign this error or any error that it wraps to a *PathError",
+		res.description,
+		"got expected description",
+	)
+}
+
+func errorsErrorAsComparers(t *testing.T) {
+	err := fmt.Errorf("open: %w", &fs.PathError{Op: "open", Path: "/x", Err: fs.ErrNotExist})
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	var target *fs.PathError
+	r.Passes(err, r.ErrorAs(&target, r.Deref(r.Struct(func(st *StructTester) {
+		st.Field("Path", "/y")
+		st.Etc()
+	}))), "ErrorAs")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"*wrapError"}},
+			{pass: false, dataPath: []string{"*wrapError", "*", "PathError", ".Path", "string"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(t, "/x", target.Path, "target was set")
+}
+
+func errorsErrorAsBadTarget(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	var target fs.PathError
+	r.Passes(errTestSentinel, r.ErrorAs(&target), "ErrorAs")
+	mockT.AssertCalled(t, "Fail")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inUsage, res.where, "failure is a usage error")
+	assert.Equal(
+		t,
+		"You passed a *PathError to ErrorAs() but it needs a non-nil pointer to an interface or to a type that implements error",
+		res.description,
+		"got expected description",
+	)
+}
+
+func errorsErrorMessagePasses(t *testing.T) {
+	err := fmt.Errorf("outer: %w", errTestSentinel)
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(err, d.ErrorMessage("outer: sentinel"), "string")
+	d.Passes(err, d.ErrorMessage(d.HasPrefix("outer")), "comparer")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: string\n")
+}
+
+func errorsErrorMessageFails(t *testing.T) {
+	err := fmt.Errorf("outer: %w", errTestSentinel)
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(err, r.ErrorMessage(r.HasPrefix("inner")), "ErrorMessage")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"*wrapError", "Error()", "string"}},
+			{pass: false, dataPath: []string{"*wrapError"}},
+			{pass: false, dataPath: []string{"*wrapError", "Unwrap()"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(t, "has prefix", r.record[0].output[0].result.op, "first failure is from HasPrefix")
+	assert.Equal(t, "Error()", r.record[0].output[2].result.op, "chain failures show the message")
+}
+
+func errorsNoErrorAndAnErrorPass(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(nil, d.NoError(), "NoError")
+	d.Passes(errTestSentinel, d.AnError(), "AnError")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: NoError\n")
+}
+
+func errorsNoErrorFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(fmt.Errorf("outer: %w", errTestSentinel), r.NoError(), "NoError")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"*wrapError"}},
+			{pass: false, dataPath: []string{"*wrapError", "Unwrap()"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Expected no error but got one",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func errorsAnErrorFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(nil, r.AnError(), "AnError")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{{pass: false, dataPath: []string{"nil"}}},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Expected an error but got nil",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func errorsNonError(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes("not an error", r.NoError(), "NoError")
+	mockT.AssertCalled(t, "Fail")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inType, res.where, "failure is in the type")
+	assert.Equal(
+		t,
+		"Called detest.NoError() but the value being tested isn't an error, it's a string",
+		res.description,
+		"got expected description",
+	)
+}
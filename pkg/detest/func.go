@@ -35,16 +35,14 @@ func (d *D) FuncFor(with interface{}, called string) (FuncComparer, error) {
 }
 
 func (d *D) newFunc(with interface{}, name, called string) (FuncComparer, error) {
-	v := reflect.ValueOf(with)
-	t := v.Type()
-	if v.Kind() != reflect.Func {
-		return FuncComparer{},
-			fmt.Errorf("you passed %s to %s but it needs a function", articleize(describeType(t)), called)
+	v, err := funcValue(with, called)
+	if err != nil {
+		return FuncComparer{}, err
 	}
 
-	if t.NumIn() != 1 {
-		return FuncComparer{},
-			fmt.Errorf("the function passed to %s must take 1 value, but yours takes %d", called, t.NumIn())
+	t := v.Type()
+	if err := checkNumIn(t, 1, called); err != nil {
+		return FuncComparer{}, err
 	}
 	if !(t.NumOut() == 1 || t.NumOut() == 2) {
 		return FuncComparer{},
@@ -75,21 +73,7 @@ func (fc FuncComparer) Compare(d *D) {
 	defer d.PopPath()
 
 	inType := fc.comparer.Type().In(0)
-	okInput := false
-	if v.IsValid() {
-		// Either the types are the same or the input type is an interface and
-		// the value implements it.
-		if v.Type() == inType ||
-			(inType.Kind() == reflect.Interface && v.Type().Implements(inType)) {
-			okInput = true
-		}
-	} else {
-		if isNilable(inType.Kind()) {
-			okInput = true
-		}
-	}
-
-	if !okInput {
+	if !acceptsValue(inType, v) {
 		d.AddResult(result{
 			actual: newValue(actual),
 			pass:   false,
@@ -104,23 +88,7 @@ func (fc FuncComparer) Compare(d *D) {
 		return
 	}
 
-	// If it's a bare nil we need to make a zero value of whatever type the
-	// func is expecting. If we try to just pass the (invalid) bare nil, then
-	// the `.Call(...)` will panic with "Call using zero Value argument".
-	//
-	// This seems really wonky but AFAICT this is actually what the
-	// interpreter is doing to! Run this code to see it in action:
-	//
-	// f := func(s []int) {
-	//     log.Printf("%v", reflect.TypeOf(s))
-	// }
-	// f(s)
-	// f(nil)
-
-	if !v.IsValid() {
-		v = reflect.Zero(inType)
-	}
-	ret := fc.comparer.Call([]reflect.Value{v})
+	ret := fc.comparer.Call([]reflect.Value{argumentValue(inType, v)})
 	r := result{
 		actual: newValue(actual),
 		pass:   ret[0].Bool(),
@@ -135,3 +103,134 @@ func (fc FuncComparer) Compare(d *D) {
 
 	d.AddResult(r)
 }
+
+// funcValue returns the reflect.Value for with, or an error if it isn't a
+// non-nil function.
+func funcValue(with interface{}, called string) (reflect.Value, error) {
+	v := reflect.ValueOf(with)
+	if v.Kind() != reflect.Func {
+		return reflect.Value{},
+			fmt.Errorf("you passed %s to %s but it needs a function", articleize(describeTypeOfReflectValue(v)), called)
+	}
+	if v.IsNil() {
+		return reflect.Value{},
+			fmt.Errorf("you passed a nil %s to %s but it needs a non-nil function", describeType(v.Type()), called)
+	}
+	return v, nil
+}
+
+// checkNumIn returns an error if a function of type t does not take exactly
+// n values.
+func checkNumIn(t reflect.Type, n int, called string) error {
+	if t.NumIn() == n {
+		return nil
+	}
+
+	values := "values"
+	if n == 1 {
+		values = "value"
+	}
+	return fmt.Errorf("the function passed to %s must take %d %s, but yours takes %d", called, n, values, t.NumIn())
+}
+
+// checkNumArgs returns an error if n arguments cannot be passed to a function
+// of type t. Unlike checkNumIn, this is for when the arguments are given and
+// the function is what's being checked, so the error is about the arguments.
+// The fn argument describes the function in the error, like "the function
+// passed to detest.Call()".
+func checkNumArgs(t reflect.Type, n int, fn string) error {
+	takes := t.NumIn()
+	atLeast := ""
+	if t.IsVariadic() {
+		takes--
+		if n >= takes {
+			return nil
+		}
+		atLeast = "at least "
+	} else if n == takes {
+		return nil
+	}
+
+	values := "values"
+	if takes == 1 {
+		values = "value"
+	}
+	return fmt.Errorf("%s takes %s%d %s, but you passed %d", fn, atLeast, takes, values, n)
+}
+
+// acceptsValue returns true if v can be passed to a function parameter of the
+// given type.
+func acceptsValue(inType reflect.Type, v reflect.Value) bool {
+	if !v.IsValid() {
+		return isNilable(inType.Kind())
+	}
+
+	// Either the types are the same or the input type is an interface and
+	// the value implements it.
+	return v.Type() == inType ||
+		(inType.Kind() == reflect.Interface && v.Type().Implements(inType))
+}
+
+// argumentValue returns the value to pass to a function parameter of the
+// given type.
+//
+// If it's a bare nil we need to make a zero value of whatever type the func
+// is expecting. If we try to just pass the (invalid) bare nil, then the
+// `.Call(...)` will panic with "Call using zero Value argument".
+//
+// This seems really wonky but AFAICT this is actually what the interpreter is
+// doing to! Run this code to see it in action:
+//
+//	f := func(s []int) {
+//	    log.Printf("%v", reflect.TypeOf(s))
+//	}
+//	f(s)
+//	f(nil)
+func argumentValue(inType reflect.Type, v reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Zero(inType)
+	}
+	return v
+}
+
+// argumentValues returns the values to pass to a function of type t when
+// calling it with args. The caller must already have checked that t takes
+// this number of arguments. If one of the arguments cannot be passed to the
+// function, it returns an error saying which one. The takes argument names
+// the function in the error, like "the function" or "the Get method".
+func argumentValues(t reflect.Type, args []interface{}, called, takes string) ([]reflect.Value, error) {
+	variadic := t.IsVariadic() && len(args) >= t.NumIn()-1
+
+	in := make([]reflect.Value, len(args))
+	for i, a := range args {
+		var inType reflect.Type
+		if variadic && i >= t.NumIn()-1 {
+			inType = t.In(t.NumIn() - 1).Elem()
+		} else {
+			inType = t.In(i)
+		}
+
+		av := reflect.ValueOf(a)
+		// Unlike a comparison func, which always gets the value being tested,
+		// these are arguments the user wrote, so we accept anything Go would
+		// accept in a call. This includes values of a named type passed for
+		// its unnamed underlying type or vice versa.
+		assignable := isNilable(inType.Kind())
+		if av.IsValid() {
+			assignable = av.Type().AssignableTo(inType)
+		}
+		if !assignable {
+			return nil, fmt.Errorf(
+				"argument %d passed to %s is %s but %s takes %s",
+				i,
+				called,
+				articleize(describeTypeOfReflectValue(av)),
+				takes,
+				articleize(describeType(inType)),
+			)
+		}
+		in[i] = argumentValue(inType, av)
+	}
+
+	return in, nil
+}
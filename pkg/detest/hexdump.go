@@ -0,0 +1,149 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/houseabsolute/detest/pkg/detest/internal/ansi"
+)
+
+// The number of bytes shown in each row of a hex dump. This is smaller than
+// the usual 16 so that two dumps fit side by side in a terminal.
+const hexDumpWidth = 8
+
+// The width of one side of a dump row: 8 hex pairs with spaces between them,
+// then two spaces and the ASCII column wrapped in pipes.
+const hexDumpSideWidth = hexDumpWidth*3 - 1 + 2 + hexDumpWidth + 2
+
+// hexDump returns a side by side hex and ASCII dump of the actual and expected
+// values when both are byte slices and the result is from an equality
+// comparison. Byte slices which both contain multi-line text are left to
+// `unifiedDiff`. Otherwise this returns an empty string.
+func (r result) hexDump(s ansi.Scheme) string {
+	if r.where != inValue || !r.isEquality() || !r.showActual() || !r.showExpect() {
+		return ""
+	}
+
+	got, ok := byteSlice(r.actual.value)
+	if !ok {
+		return ""
+	}
+	expect, ok := byteSlice(r.expect.value)
+	if !ok {
+		return ""
+	}
+	if isText(got) && isText(expect) && (strings.Contains(string(got), "\n") || strings.Contains(string(expect), "\n")) {
+		return ""
+	}
+
+	return formatHexDump(got, expect, s)
+}
+
+func byteSlice(val interface{}) ([]byte, bool) {
+	v := reflect.ValueOf(val)
+	if !v.IsValid() || v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, false
+	}
+	return v.Bytes(), true
+}
+
+func isText(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatHexDump renders the two slices side by side. Bytes that differ are
+// highlighted, as is the offset of the row containing the first difference.
+// Runs of rows that are identical in both slices, and which aren't next to a
+// row with a difference, are elided.
+func formatHexDump(got, expect []byte, s ansi.Scheme) string {
+	n := max(len(got), len(expect))
+	rows := (n + hexDumpWidth - 1) / hexDumpWidth
+
+	differs := make([]bool, rows)
+	firstRow := -1
+	for r := 0; r < rows; r++ {
+		for i := r * hexDumpWidth; i < (r+1)*hexDumpWidth && i < n; i++ {
+			if i >= len(got) || i >= len(expect) || got[i] != expect[i] {
+				differs[r] = true
+				break
+			}
+		}
+		if differs[r] && firstRow == -1 {
+			firstRow = r
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(
+		s.Strong(fmt.Sprintf("%-8s  %-*s    %s", "OFFSET", hexDumpSideWidth, "GOT", "EXPECT")) + "\n",
+	)
+
+	elided := 0
+	for r := 0; r < rows; r++ {
+		show := differs[r] || (r > 0 && differs[r-1]) || (r < rows-1 && differs[r+1])
+		if !show {
+			elided += min(n, (r+1)*hexDumpWidth) - r*hexDumpWidth
+			continue
+		}
+		if elided > 0 {
+			b.WriteString(fmt.Sprintf("... %d identical bytes ...\n", elided))
+			elided = 0
+		}
+
+		offset := fmt.Sprintf("%08x", r*hexDumpWidth)
+		if r == firstRow {
+			offset = s.Incorrect(offset)
+		}
+		b.WriteString(
+			offset + "  " +
+				hexDumpSide(got, expect, r, s.Incorrect) + "    " +
+				hexDumpSide(expect, got, r, s.Correct) + "\n",
+		)
+	}
+	if elided > 0 {
+		b.WriteString(fmt.Sprintf("... %d identical bytes ...\n", elided))
+	}
+
+	return b.String()
+}
+
+// hexDumpSide renders one row of one slice. Bytes which are not the same in
+// the other slice are highlighted. Bytes past the end of the slice are left
+// blank.
+func hexDumpSide(b, other []byte, row int, highlight func(string) string) string {
+	hex := make([]string, hexDumpWidth)
+	var ascii strings.Builder
+	for i := 0; i < hexDumpWidth; i++ {
+		idx := row*hexDumpWidth + i
+		if idx >= len(b) {
+			hex[i] = "  "
+			ascii.WriteString(" ")
+			continue
+		}
+
+		h := fmt.Sprintf("%02x", b[idx])
+		c := "."
+		if b[idx] >= 0x20 && b[idx] < 0x7f {
+			c = string(rune(b[idx]))
+		}
+		if idx >= len(other) || other[idx] != b[idx] {
+			h = highlight(h)
+			c = highlight(c)
+		}
+		hex[i] = h
+		ascii.WriteString(c)
+	}
+
+	return strings.Join(hex, " ") + "  |" + ascii.String() + "|"
+}
@@ -0,0 +1,148 @@
+package detest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/houseabsolute/detest/pkg/detest/internal/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexDump(t *testing.T) {
+	t.Run("formatHexDump", hexDumpFormat)
+	t.Run("Different lengths", hexDumpDifferentLengths)
+	t.Run("Failure path", hexDumpFailurePath)
+	t.Run("Failure output", hexDumpFailureOutput)
+	t.Run("Multi-line text uses a diff", hexDumpNotUsedForText)
+	t.Run("Not used for comparers other than equality", hexDumpNotUsedForOtherComparers)
+}
+
+func hexDumpFormat(t *testing.T) {
+	got := make([]byte, 48)
+	expect := make([]byte, 48)
+	for i := range got {
+		got[i] = byte('a' + i%26)
+		expect[i] = got[i]
+	}
+	got[20] = 0x00
+
+	assert.Equal(
+		t,
+		strings.Join([]string{
+			"OFFSET    GOT                                    EXPECT",
+			"... 8 identical bytes ...",
+			"00000008  69 6a 6b 6c 6d 6e 6f 70  |ijklmnop|    69 6a 6b 6c 6d 6e 6f 70  |ijklmnop|",
+			"00000010  71 72 73 74 00 76 77 78  |qrst.vwx|    71 72 73 74 75 76 77 78  |qrstuvwx|",
+			"00000018  79 7a 61 62 63 64 65 66  |yzabcdef|    79 7a 61 62 63 64 65 66  |yzabcdef|",
+			"... 16 identical bytes ...",
+			"",
+		}, "\n"),
+		ansi.Strip(formatHexDump(got, expect, ansi.DefaultScheme)),
+		"got expected hex dump",
+	)
+}
+
+func hexDumpDifferentLengths(t *testing.T) {
+	assert.Equal(
+		t,
+		strings.Join([]string{
+			"OFFSET    GOT                                    EXPECT",
+			"00000000  01 02 03 04 05 06 07 08  |........|    01 02 03 04 05 06 07 08  |........|",
+			"00000008  09 0a                    |..      |    09 0a 0b                 |...     |",
+			"",
+		}, "\n"),
+		ansi.Strip(formatHexDump(
+			[]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			[]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			ansi.DefaultScheme,
+		)),
+		"got expected hex dump",
+	)
+}
+
+func hexDumpFailurePath(t *testing.T) {
+	got := make([]byte, 0x200)
+	expect := make([]byte, 0x200)
+	got[0x1f4] = 0xff
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string][]byte{"payload": got},
+		map[string][]byte{"payload": expect},
+		"byte slices",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"map[string][]uint8", "[payload]", "[0x1f4]"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"The byte slices first differ at offset 0x1f4 (500)",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func hexDumpFailureOutput(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is([]byte{0xde, 0xad, 0xbe, 0xef}, []byte{0xde, 0xad, 0xbe}, "byte slices")
+	mockT.AssertCalled(t, "Fail")
+
+	call := mockT.FindCall("WriteString")
+	if !assert.NotNil(t, call, "WriteString was called") {
+		return
+	}
+	output := ansi.Strip(call.Args[0].(string))
+	assert.Contains(t, output, "<see hex dump below>", "table refers to the hex dump")
+	assert.Contains(
+		t,
+		output,
+		"00000000  de ad be ef              |....    |    de ad be                 |...     |\n",
+		"output contains a hex dump",
+	)
+	assert.Contains(
+		t,
+		output,
+		"The byte slices first differ at offset 0x3 (3). The actual slice has 4 bytes but the expected slice has 3",
+		"output contains the description",
+	)
+}
+
+func hexDumpNotUsedForText(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is([]byte("a\nb\n"), []byte("a\nc\n"), "byte slices")
+	mockT.AssertCalled(t, "Fail")
+
+	call := mockT.FindCall("WriteString")
+	if !assert.NotNil(t, call, "WriteString was called") {
+		return
+	}
+	output := ansi.Strip(call.Args[0].(string))
+	assert.Contains(t, output, "<see diff below>", "table refers to the diff")
+	assert.NotContains(t, output, "OFFSET", "output does not contain a hex dump")
+}
+
+func hexDumpNotUsedForOtherComparers(t *testing.T) {
+	r := result{
+		actual: newValue([]byte{0xde, 0xad}),
+		expect: newValue([]byte{0xbe, 0xef}),
+		op:     "contains",
+		where:  inValue,
+	}
+	assert.Equal(t, "", r.hexDump(ansi.DefaultScheme), "no hex dump for a contains result")
+
+	r.op = "=="
+	assert.NotEqual(t, "", r.hexDump(ansi.DefaultScheme), "hex dump for an equality result")
+}
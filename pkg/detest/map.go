@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 )
 
 // MapComparer implements comparison of map values.
@@ -32,9 +33,16 @@ type MapTester struct {
 func (mc MapComparer) Compare(d *D) {
 	v := reflect.ValueOf(d.Actual())
 
-	d.PushPath(d.NewPath(describeTypeOfReflectValue(v), 1, "detest.(*D).Map"))
+	path := d.NewPath(describeTypeOfReflectValue(v), 1, "detest.(*D).Map")
+	d.PushPath(path)
 	defer d.PopPath()
 
+	v, undo, ok := d.derefActual(path, "Map()")
+	defer undo()
+	if !ok {
+		return
+	}
+
 	if v.Kind() != reflect.Map {
 		d.AddResult(result{
 			actual: newValue(d.Actual()),
@@ -62,6 +70,137 @@ func (mt *MapTester) Key(key interface{}, expect interface{}) {
 	mt.d.PushPath(mt.d.NewPath(fmt.Sprintf("[%v]", key), 0, ""))
 	defer mt.d.PopPath()
 
+	kv, ok := mt.keyValue(key)
+	if !ok {
+		return
+	}
+
+	found := v.MapIndex(kv)
+	if !found.IsValid() {
+		mt.d.AddResult(result{
+			actual:      newValue(mt.d.Actual()),
+			pass:        false,
+			where:       inDataStructure,
+			op:          fmt.Sprintf("[%v]", key),
+			description: "Attempted to get a map key that does not exist",
+		})
+		return
+	}
+
+	mt.d.PushActual(found.Interface())
+	defer mt.d.PopActual()
+
+	mt.seen[key] = true
+
+	if c, ok := expect.(Comparer); ok {
+		c.Compare(mt.d)
+	} else {
+		mt.d.Equal(expect).Compare(mt.d)
+	}
+}
+
+// NoKey takes a key and checks that it does not exist in the map. This is
+// useful for checking that a key was removed.
+func (mt *MapTester) NoKey(key interface{}) {
+	v := reflect.ValueOf(mt.d.Actual())
+
+	mt.d.PushPath(mt.d.NewPath(fmt.Sprintf("[%v]", key), 0, ""))
+	defer mt.d.PopPath()
+
+	kv, ok := mt.keyValue(key)
+	if !ok {
+		return
+	}
+
+	result := result{
+		op:   "no key",
+		pass: true,
+	}
+	if found := v.MapIndex(kv); found.IsValid() {
+		result.actual = newValue(found.Interface())
+		result.pass = false
+		result.where = inValue
+		result.description = fmt.Sprintf("The map has the key %v but you expected it not to", key)
+	}
+	mt.d.AddResult(result)
+}
+
+// KeysAre takes a list of keys and checks that the map has exactly those keys.
+// Any keys which are missing from the map or which the map has but were not
+// passed are considered a failure. This does not count as checking the values
+// of those keys for `End()`.
+func (mt *MapTester) KeysAre(keys ...interface{}) {
+	mt.d.PushPath(mt.d.NewPath("keys", 0, ""))
+	defer mt.d.PopPath()
+
+	mt.checkKeys(keys, true)
+}
+
+// KeysInclude takes a list of keys and checks that the map has all of those
+// keys. The map may have other keys as well. Any keys which are missing from
+// the map are considered a failure. This does not count as checking the
+// values of those keys for `End()`.
+func (mt *MapTester) KeysInclude(keys ...interface{}) {
+	mt.d.PushPath(mt.d.NewPath("keys", 0, ""))
+	defer mt.d.PopPath()
+
+	mt.checkKeys(keys, false)
+}
+
+// checkKeys checks that the map has all of the given keys. If exact is true,
+// it also checks that the map has no other keys.
+func (mt *MapTester) checkKeys(keys []interface{}, exact bool) {
+	v := reflect.ValueOf(mt.d.Actual())
+
+	expect := map[interface{}]bool{}
+	var missing []reflect.Value
+	for _, k := range keys {
+		kv, ok := mt.keyValue(k)
+		if !ok {
+			return
+		}
+		expect[kv.Interface()] = true
+		if !v.MapIndex(kv).IsValid() {
+			missing = append(missing, kv)
+		}
+	}
+
+	op := "keys include"
+	var unexpected []reflect.Value
+	if exact {
+		op = "keys are"
+		for _, k := range v.MapKeys() {
+			if !expect[k.Interface()] {
+				unexpected = append(unexpected, k)
+			}
+		}
+	}
+
+	result := result{
+		actual: &value{value: describeMapKeys(v.MapKeys()), desc: describeType(v.Type().Key())},
+		expect: &value{value: describeMapKeys(keyValues(keys)), desc: describeType(v.Type().Key())},
+		op:     op,
+		pass:   len(missing) == 0 && len(unexpected) == 0,
+	}
+	if !result.pass {
+		var desc []string
+		if len(missing) > 0 {
+			desc = append(desc, fmt.Sprintf("The map is missing these keys: %s", describeMapKeys(missing)))
+		}
+		if len(unexpected) > 0 {
+			desc = append(desc, fmt.Sprintf("The map has these unexpected keys: %s", describeMapKeys(unexpected)))
+		}
+		result.where = inValue
+		result.description = strings.Join(desc, "\n")
+	}
+	mt.d.AddResult(result)
+}
+
+// keyValue returns the reflect.Value for the given key. If the key's type
+// does not match the map's key type it adds a failure and returns false.
+func (mt *MapTester) keyValue(key interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(mt.d.Actual())
+
 	kv := reflect.ValueOf(key)
 	if !kv.IsValid() || kv.Type() != v.Type().Key() {
 		mt.d.AddResult(result{
@@ -75,25 +214,81 @@ func (mt *MapTester) Key(key interface{}, expect interface{}) {
 				articleize(describeType(v.Type().Key())),
 			),
 		})
-		return
+		return reflect.Value{}, false
 	}
 
-	found := v.MapIndex(kv)
-	if !found.IsValid() {
+	return kv, true
+}
+
+// keyValues returns the reflect.Value of each key.
+func keyValues(keys []interface{}) []reflect.Value {
+	values := make([]reflect.Value, len(keys))
+	for i, k := range keys {
+		values[i] = reflect.ValueOf(k)
+	}
+	return values
+}
+
+// KeyMatching takes a comparer for keys and an expected value. It searches
+// all of the map's keys for one that passes the key comparer and then checks
+// that key's value against the expected value. This is useful for maps with
+// keys that you cannot construct in your test, like pointers or generated
+// IDs. If no keys match, or if more than one key matches, this is considered
+// a failure. If the key comparer reports a usage error, that error is
+// reported instead.
+func (mt *MapTester) KeyMatching(key Comparer, expect interface{}) {
+	v := reflect.ValueOf(mt.d.Actual())
+
+	mt.d.PushPath(mt.d.NewPath("[?]", 0, ""))
+	var matches []reflect.Value
+	for _, k := range v.MapKeys() {
+		mt.d.PushActual(k.Interface())
+		pass, output := mt.d.evaluate(key)
+		mt.d.PopActual()
+		// A usage error will be the same for every key, so there's no point
+		// in checking the rest of them or in saying that none matched.
+		if hasUsageError(output) {
+			mt.d.state.output = append(mt.d.state.output, output...)
+			mt.d.PopPath()
+			return
+		}
+		if pass {
+			matches = append(matches, k)
+		}
+	}
+
+	if len(matches) != 1 {
+		description := fmt.Sprintf(
+			"None of the map's keys matched the key comparer. The keys are: %s",
+			describeMapKeys(v.MapKeys()),
+		)
+		if len(matches) > 1 {
+			description = fmt.Sprintf(
+				"%d of the map's keys matched the key comparer but it must match exactly one. The matching keys are: %s",
+				len(matches),
+				describeMapKeys(matches),
+			)
+		}
 		mt.d.AddResult(result{
 			actual:      newValue(mt.d.Actual()),
 			pass:        false,
 			where:       inDataStructure,
-			op:          fmt.Sprintf("[%v]", key),
-			description: "Attempted to get a map key that does not exist",
+			op:          "[?]",
+			description: description,
 		})
+		mt.d.PopPath()
 		return
 	}
+	mt.d.PopPath()
 
-	mt.d.PushActual(found.Interface())
+	k := matches[0]
+	mt.d.PushPath(mt.d.NewPath(fmt.Sprintf("[%v]", k), 0, ""))
+	defer mt.d.PopPath()
+
+	mt.d.PushActual(v.MapIndex(k).Interface())
 	defer mt.d.PopActual()
 
-	mt.seen[key] = true
+	mt.seen[k.Interface()] = true
 
 	if c, ok := expect.(Comparer); ok {
 		c.Compare(mt.d)
@@ -102,6 +297,21 @@ func (mt *MapTester) Key(key interface{}, expect interface{}) {
 	}
 }
 
+// describeMapKeys returns a sorted, comma-separated list of the given keys.
+func describeMapKeys(keys []reflect.Value) string {
+	if len(keys) == 0 {
+		return "(none)"
+	}
+
+	strs := make([]string, len(keys))
+	for i, k := range keys {
+		strs[i] = fmt.Sprintf("%v", k)
+	}
+	sort.Strings(strs)
+
+	return strings.Join(strs, ", ")
+}
+
 // AllValues takes a function and turns it into a `FuncComparer`. It then
 // passes every map value to that comparer in turn. The function must take
 // exactly one value matching the map key's type and return a single boolean
@@ -30,6 +30,15 @@ func TestMap(t *testing.T) {
 		{"Calls End but does not check all values", mapCallsEndButDoesNotCheckAllValues},
 		{"Calls End but does not check all all values with nested maps", mapNestedEndChecks},
 		{"Calls Etc and does not check all values", mapCallsEtcAndDoesNotCheckAllValues},
+		{"KeyMatching", mapKeyMatching},
+		{"KeyMatching with no matching keys", mapKeyMatchingNoMatches},
+		{"KeyMatching with multiple matching keys", mapKeyMatchingMultipleMatches},
+		{"KeyMatching with a usage error in the key comparer", mapKeyMatchingUsageError},
+		{"NoKey", mapNoKey},
+		{"NoKey with the wrong key type", mapNoKeyWrongKeyType},
+		{"KeysAre", mapKeysAre},
+		{"KeysAre with missing and unexpected keys", mapKeysAreMissingAndUnexpected},
+		{"KeysInclude", mapKeysInclude},
 	}
 
 	for _, test := range tests {
@@ -661,3 +670,271 @@ func mapCallsEtcAndDoesNotCheckAllValues(t *testing.T) {
 		"got a pass for the first result",
 	)
 }
+
+type mapKey struct {
+	ID   int
+	Name string
+}
+
+func mapKeyMatching(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[mapKey]int{{ID: 1, Name: "foo"}: 1, {ID: 2, Name: "bar"}: 2},
+		r.Map(func(mt *MapTester) {
+			mt.KeyMatching(
+				r.Struct(func(st *StructTester) {
+					st.Field("Name", "foo")
+					st.Etc()
+				}),
+				1,
+			)
+			mt.KeyMatching(
+				r.Struct(func(st *StructTester) {
+					st.Field("Name", "bar")
+					st.Etc()
+				}),
+				3,
+			)
+			mt.End()
+		}),
+		"KeyMatching",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"map[mapKey]int", "[{1 foo}]", "int"}},
+			{pass: false, dataPath: []string{"map[mapKey]int", "[{2 bar}]", "int"}},
+		},
+		"got expected results and End() saw both keys",
+	)
+	assert.Equal(
+		t,
+		"detest.mapKeyMatching.func1",
+		r.record[0].output[0].result.path[1].caller,
+		"key path has the test function as its caller",
+	)
+}
+
+func mapKeyMatchingNoMatches(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string]int{"foo": 1, "bar": 2},
+		r.Map(func(mt *MapTester) {
+			mt.KeyMatching(r.HasPrefix("baz"), 1)
+			mt.End()
+		}),
+		"KeyMatching with no matches",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"map[string]int", "[?]"}},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, inDataStructure, res.where, "failure is in the data structure")
+	assert.Equal(
+		t,
+		"None of the map's keys matched the key comparer. The keys are: bar, foo",
+		res.description,
+		"got expected description",
+	)
+}
+
+func mapKeyMatchingMultipleMatches(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string]int{"foo": 1, "bar": 2, "baz": 3},
+		r.Map(func(mt *MapTester) {
+			mt.KeyMatching(r.HasPrefix("ba"), 1)
+			mt.End()
+		}),
+		"KeyMatching with multiple matches",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	assert.Equal(
+		t,
+		"2 of the map's keys matched the key comparer but it must match exactly one. The matching keys are: bar, baz",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func mapKeyMatchingUsageError(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string]int{"foo": 1, "bar": 2},
+		r.Map(func(mt *MapTester) {
+			mt.KeyMatching(r.Between("z", "a"), 1)
+			mt.Etc()
+		}),
+		"KeyMatching with a usage error",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"map[string]int", "[?]", "string"}},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, inUsage, res.where, "failure is a usage error")
+	assert.Equal(
+		t,
+		"The low value passed to detest.(*D).Between() (z) is greater than the high value (a)",
+		res.description,
+		"got the key comparer's description",
+	)
+}
+
+func mapNoKey(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string]string{"user": "foo", "password": "bar"},
+		r.Map(func(mt *MapTester) {
+			mt.NoKey("token")
+			mt.NoKey("password")
+			mt.Etc()
+		}),
+		"NoKey",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"map[string]string", "[token]"}},
+			{pass: false, dataPath: []string{"map[string]string", "[password]"}},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[1].result
+	assert.Equal(t, inValue, res.where, "failure is in the value")
+	assert.Equal(t, &value{value: "bar", desc: "string"}, res.actual, "actual is the value of the key")
+	assert.Equal(
+		t,
+		"The map has the key password but you expected it not to",
+		res.description,
+		"got expected description",
+	)
+}
+
+func mapNoKeyWrongKeyType(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string]string{"user": "foo"},
+		r.Map(func(mt *MapTester) {
+			mt.NoKey(42)
+			mt.End()
+		}),
+		"NoKey with wrong key type",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inDataStructure, res.where, "failure is in the data structure")
+	assert.Equal(
+		t,
+		"Attempted to look up a map using a key that is an int but this map uses a string as a key",
+		res.description,
+		"got expected description",
+	)
+}
+
+func mapKeysAre(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		map[string]int{"foo": 1, "bar": 2},
+		d.Map(func(mt *MapTester) {
+			mt.KeysAre("bar", "foo")
+			mt.Etc()
+		}),
+		"KeysAre",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: KeysAre\n")
+}
+
+func mapKeysAreMissingAndUnexpected(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string]int{"foo": 1, "bar": 2, "secret": 3, "token": 4},
+		r.Map(func(mt *MapTester) {
+			mt.KeysAre("foo", "bar", "baz", "buz")
+			mt.Etc()
+		}),
+		"KeysAre with missing and unexpected keys",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"map[string]int", "keys"}},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, "keys are", res.op, "got expected op")
+	assert.Equal(
+		t,
+		"The map is missing these keys: baz, buz\nThe map has these unexpected keys: secret, token",
+		res.description,
+		"got expected description",
+	)
+}
+
+func mapKeysInclude(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string]int{"foo": 1, "bar": 2, "secret": 3},
+		r.Map(func(mt *MapTester) {
+			mt.KeysInclude("foo", "bar")
+			mt.KeysInclude("foo", "baz")
+			mt.Etc()
+		}),
+		"KeysInclude",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"map[string]int", "keys"}},
+			{pass: false, dataPath: []string{"map[string]int", "keys"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"The map is missing these keys: baz",
+		r.record[0].output[1].result.description,
+		"got expected description",
+	)
+}
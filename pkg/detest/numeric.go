@@ -0,0 +1,347 @@
+package detest
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// ApproxComparer implements comparison of numbers with an absolute tolerance.
+type ApproxComparer struct {
+	expect    interface{}
+	tolerance float64
+}
+
+// Approx takes an expected number and an absolute tolerance. The actual value
+// passes if it differs from the expected value by no more than the tolerance.
+// Both values may be of any integer or float type. Two integers are compared
+// exactly, while a comparison involving a float is done with `float64`
+// values.
+func (d *D) Approx(expect interface{}, tolerance float64) ApproxComparer {
+	return ApproxComparer{expect, tolerance}
+}
+
+// Compare compares the number in d.Actual() to the expected number passed to
+// Approx().
+func (ac ApproxComparer) Compare(d *D) {
+	nc := numericCheck{
+		called:    "detest.(*D).Approx",
+		op:        "within ±" + formatFloat(ac.tolerance),
+		tolerance: ac.tolerance,
+		check: func(_, _, delta float64, _ bool) (bool, string) {
+			if delta <= ac.tolerance {
+				return true, ""
+			}
+			return false, fmt.Sprintf(
+				"The values differ by %s, which is more than the tolerance of %s",
+				formatFloat(delta), formatFloat(ac.tolerance),
+			)
+		},
+	}
+	nc.compare(d, ac.expect)
+}
+
+// PercentComparer implements comparison of numbers with a tolerance relative
+// to the expected value.
+type PercentComparer struct {
+	expect  interface{}
+	percent float64
+}
+
+// WithinPercent takes an expected number and a percentage. The actual value
+// passes if it differs from the expected value by no more than that
+// percentage of the expected value. If the expected value is 0 then the
+// actual value must be 0 as well.
+func (d *D) WithinPercent(expect interface{}, percent float64) PercentComparer {
+	return PercentComparer{expect, percent}
+}
+
+// Compare compares the number in d.Actual() to the expected number passed to
+// WithinPercent().
+func (pc PercentComparer) Compare(d *D) {
+	nc := numericCheck{
+		called:    "detest.(*D).WithinPercent",
+		op:        "within " + formatFloat(pc.percent) + "%",
+		tolerance: pc.percent,
+		check: func(_, expect, delta float64, _ bool) (bool, string) {
+			if expect == 0 {
+				return false, "The expected value is 0, so the actual value must be 0 as well"
+			}
+			percent := delta / math.Abs(expect) * 100
+			if percent <= pc.percent {
+				return true, ""
+			}
+			return false, fmt.Sprintf(
+				"The values differ by %s%% of the expected value, which is more than the tolerance of %s%%",
+				formatFloat(percent), formatFloat(pc.percent),
+			)
+		},
+	}
+	nc.compare(d, pc.expect)
+}
+
+// ULPComparer implements comparison of numbers by counting the floating point
+// values between them.
+type ULPComparer struct {
+	expect interface{}
+	ulp    uint64
+}
+
+// WithinULP takes an expected number and a number of ULPs ("units in the last
+// place"). The actual value passes if there are no more than this many
+// representable floating point values between it and the expected value. If
+// either value is a `float32` and neither is a `float64` then the distance is
+// measured in `float32` values, otherwise it's measured in `float64` values.
+func (d *D) WithinULP(expect interface{}, ulp uint64) ULPComparer {
+	return ULPComparer{expect, ulp}
+}
+
+// Compare compares the number in d.Actual() to the expected number passed to
+// WithinULP().
+func (uc ULPComparer) Compare(d *D) {
+	nc := numericCheck{
+		called:         "detest.(*D).WithinULP",
+		op:             fmt.Sprintf("within %d ULP", uc.ulp),
+		tolerance:      float64(uc.ulp),
+		measuresFloats: true,
+		check: func(actual, expect, _ float64, is32 bool) (bool, string) {
+			dist := ulpDistance(actual, expect, is32)
+			if dist <= uc.ulp {
+				return true, ""
+			}
+			return false, fmt.Sprintf(
+				"The values are %d ULP apart, which is more than the tolerance of %d ULP",
+				dist, uc.ulp,
+			)
+		},
+	}
+	nc.compare(d, uc.expect)
+}
+
+// numericCheck contains the parts of a tolerance comparison that differ
+// between the numeric comparers. The check func is only called with two
+// finite numbers that are not equal, along with the absolute difference
+// between them. If measuresFloats is true, the check func uses the float
+// values rather than the difference.
+type numericCheck struct {
+	called         string
+	op             string
+	tolerance      float64
+	measuresFloats bool
+	check          func(actual, expect, delta float64, is32 bool) (bool, string)
+}
+
+func (nc numericCheck) compare(d *D, expect interface{}) {
+	actual := d.Actual()
+	actualVal := reflect.ValueOf(actual)
+	d.PushPath(d.NewPath(describeTypeOfReflectValue(actualVal), 2, nc.called))
+	defer d.PopPath()
+
+	result := result{
+		actual: newValue(actual),
+		expect: newValue(expect),
+		op:     nc.op,
+	}
+
+	if nc.tolerance < 0 || math.IsNaN(nc.tolerance) {
+		result.where = inUsage
+		result.description = fmt.Sprintf(
+			"The tolerance passed to %s() must be a positive number or zero, but you passed %s",
+			nc.called, formatFloat(nc.tolerance),
+		)
+		d.AddResult(result)
+		return
+	}
+
+	expectVal := reflect.ValueOf(expect)
+	if !isRealNumber(expectVal) {
+		result.where = inUsage
+		result.description = fmt.Sprintf(
+			"You passed %s to %s() but it needs an integer or float",
+			articleize(describeTypeOfReflectValue(expectVal)), nc.called,
+		)
+		d.AddResult(result)
+		return
+	}
+
+	if !isRealNumber(actualVal) {
+		result.where = inType
+		result.description = fmt.Sprintf(
+			"Called %s() but the value being tested isn't an integer or float, it's %s",
+			nc.called, articleize(describeTypeOfReflectValue(actualVal)),
+		)
+		d.AddResult(result)
+		return
+	}
+
+	if isInteger(actualVal) && isInteger(expectVal) {
+		result.pass, result.description = nc.checkIntegers(actualVal, expectVal)
+	} else {
+		// We convert both values to float64 directly rather than converting
+		// one to the other's type, since that can overflow, for example when
+		// comparing a negative int to a uint.
+		is32 := isFloat32Comparison(actualVal.Kind(), expectVal.Kind())
+		a := actualVal.Convert(float64Type).Float()
+		e := expectVal.Convert(float64Type).Float()
+		result.pass, result.description = nc.checkSpecialValues(a, e, is32)
+	}
+	if !result.pass {
+		result.where = inValue
+	}
+	d.AddResult(result)
+}
+
+// checkIntegers compares two integers without going through float64, which
+// would lose precision for values bigger than 2^53. Only the difference
+// between the values is converted to a float for the check func.
+func (nc numericCheck) checkIntegers(actual, expect reflect.Value) (bool, string) {
+	var delta float64
+
+	// Like compareNumbers, we handle mixed signedness before converting
+	// anything, since converting a negative int to a uint would overflow.
+	switch {
+	case actual.CanInt() && expect.CanUint():
+		delta = mixedIntegerDelta(actual.Int(), expect.Uint())
+	case actual.CanUint() && expect.CanInt():
+		delta = mixedIntegerDelta(expect.Int(), actual.Uint())
+	default:
+		a, e, _ := safelyConvertNumberTypes(actual, expect, isNumeric(actual), isNumeric(expect))
+		if a.CanInt() {
+			x, y := a.Int(), e.Int()
+			if x < y {
+				x, y = y, x
+			}
+			// The difference between two int64 values always fits in a
+			// uint64.
+			delta = float64(uint64(x) - uint64(y))
+		} else {
+			delta = float64(uintDelta(a.Uint(), e.Uint()))
+		}
+	}
+
+	if delta == 0 {
+		return true, ""
+	}
+
+	a, e := actual.Convert(float64Type).Float(), expect.Convert(float64Type).Float()
+	// Two integers that differ can convert to the same float. A check that
+	// measures the floats would see them as equal, so with a tolerance of 0
+	// we fail here instead.
+	if nc.measuresFloats && nc.tolerance == 0 && a == e {
+		return false, fmt.Sprintf(
+			"The values differ by %s but they convert to the same float64, so they cannot be told apart in ULP",
+			formatFloat(delta),
+		)
+	}
+	return nc.check(a, e, delta, false)
+}
+
+// mixedIntegerDelta returns the absolute difference between an int and a
+// uint.
+func mixedIntegerDelta(i int64, u uint64) float64 {
+	if i < 0 {
+		// The difference may not fit in a uint64 so we do this one with
+		// floats. The result is only inexact when it is bigger than 2^53.
+		return float64(u) - float64(i)
+	}
+	return float64(uintDelta(uint64(i), u))
+}
+
+func uintDelta(x, y uint64) uint64 {
+	if x < y {
+		return y - x
+	}
+	return x - y
+}
+
+var float64Type = reflect.TypeOf(float64(0))
+
+// checkSpecialValues handles NaN and infinite values before calling the check
+// func. Two NaNs are considered equal to each other, and an infinite value is
+// only equal to an infinite value with the same sign. Positive and negative
+// zero are equal, since `-0 == 0` is true.
+func (nc numericCheck) checkSpecialValues(actual, expect float64, is32 bool) (bool, string) {
+	actualNaN, expectNaN := math.IsNaN(actual), math.IsNaN(expect)
+	switch {
+	case actualNaN && expectNaN:
+		return true, ""
+	case actualNaN:
+		return false, "The actual value is NaN but the expected value is not"
+	case expectNaN:
+		return false, "The expected value is NaN but the actual value is not"
+	}
+
+	if actual == expect {
+		return true, ""
+	}
+
+	if math.IsInf(actual, 0) || math.IsInf(expect, 0) {
+		return false, "An infinite value is only close to an infinite value with the same sign"
+	}
+
+	return nc.check(actual, expect, math.Abs(actual-expect), is32)
+}
+
+// isRealNumber returns true for integers and floats. It returns false for
+// complex numbers, since they have no ordering.
+func isRealNumber(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
+	}
+	n := isNumeric(v)
+	return n != nil && n.baseType != "complex"
+}
+
+// isInteger returns true for signed and unsigned integers.
+func isInteger(v reflect.Value) bool {
+	n := isNumeric(v)
+	return n != nil && (n.baseType == intBase || n.baseType == uintBase)
+}
+
+// isFloat32Comparison returns true when the comparison should be done with
+// float32 precision. This is the case when at least one value is a float32 and
+// neither is a float64.
+func isFloat32Comparison(actual, expect reflect.Kind) bool {
+	if actual == reflect.Float64 || expect == reflect.Float64 {
+		return false
+	}
+	return actual == reflect.Float32 || expect == reflect.Float32
+}
+
+func ulpDistance(a, b float64, is32 bool) uint64 {
+	var x, y int64
+	if is32 {
+		x, y = int64(orderedFloat32(float32(a))), int64(orderedFloat32(float32(b)))
+	} else {
+		x, y = orderedFloat64(a), orderedFloat64(b)
+	}
+	if x < y {
+		x, y = y, x
+	}
+	// This can't overflow because the difference between any two of these
+	// values fits in a uint64.
+	return uint64(x) - uint64(y)
+}
+
+// orderedFloat64 maps a float's bits to an int64 so that adjacent floats map
+// to adjacent ints. Both zeros map to 0.
+func orderedFloat64(f float64) int64 {
+	i := int64(math.Float64bits(f))
+	if i < 0 {
+		i = math.MinInt64 - i
+	}
+	return i
+}
+
+func orderedFloat32(f float32) int32 {
+	i := int32(math.Float32bits(f))
+	if i < 0 {
+		i = math.MinInt32 - i
+	}
+	return i
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
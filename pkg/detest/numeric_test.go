@@ -0,0 +1,294 @@
+package detest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumeric(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"Approx passes", numericApproxPasses},
+		{"Approx outside tolerance", numericApproxOutsideTolerance},
+		{"Approx with NaN", numericApproxNaN},
+		{"Approx with infinities", numericApproxInfinities},
+		{"Approx with large integers", numericApproxLargeIntegers},
+		{"Approx with a bad tolerance or expected value", numericApproxUsageErrors},
+		{"Approx with a non-numeric actual value", numericApproxTypeErrors},
+		{"WithinPercent passes", numericWithinPercentPasses},
+		{"WithinPercent fails", numericWithinPercentFails},
+		{"WithinULP passes", numericWithinULPPasses},
+		{"WithinULP fails", numericWithinULPFails},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+func numericApproxPasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(1.05, d.Approx(1.0, 0.1), "floats")
+	d.Passes(3, d.Approx(3.01, 0.01), "int and float")
+	d.Passes(int8(-4), d.Approx(uint16(2), 6), "ints of different signedness")
+	d.Passes(2.5, d.Approx(2.5, 0), "zero tolerance")
+	d.Passes(math.Copysign(0, -1), d.Approx(0.0, 0), "zeros of different signs")
+	d.Passes(int64(1<<53+1), d.Approx(int64(1<<53), 1), "int64 values above 2^53")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: floats\n")
+}
+
+func numericApproxOutsideTolerance(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]float64{1, 2},
+		r.Slice(func(st *SliceTester) {
+			st.Idx(1, r.Approx(2.5, 0.1))
+			st.Etc()
+		}),
+		"slice of floats",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 1, "one state was recorded")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	assert.Equal(
+		t,
+		&result{
+			actual: &value{value: float64(2), desc: "float64"},
+			expect: &value{value: 2.5, desc: "float64"},
+			op:     "within ±0.1",
+			pass:   false,
+			path: []Path{
+				{
+					data:   "[]float64",
+					callee: "detest.(*D).Slice",
+					caller: "detest.(*DetestRecorder).Is",
+				},
+				{
+					data:   "[1]",
+					callee: "detest.(*SliceTester).Idx",
+					caller: "detest.numericApproxOutsideTolerance.func1",
+				},
+				{
+					data:   "float64",
+					callee: "detest.(*D).Approx",
+					caller: "detest.numericApproxOutsideTolerance.func1",
+				},
+			},
+			where:       inValue,
+			description: "The values differ by 0.5, which is more than the tolerance of 0.1",
+		},
+		r.record[0].output[0].result,
+		"got the expected result",
+	)
+}
+
+func numericApproxNaN(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(math.NaN(), r.Approx(math.NaN(), 0.1), "two NaNs")
+	r.Passes(math.NaN(), r.Approx(1.0, 0.1), "NaN actual")
+	r.Passes(1.0, r.Approx(math.NaN(), 0.1), "NaN expected")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 3, "three states were recorded")
+	assert.True(t, r.record[0].output[0].result.pass, "two NaNs are equal")
+	assert.Equal(
+		t,
+		"The actual value is NaN but the expected value is not",
+		r.record[1].output[0].result.description,
+		"got expected description for a NaN actual value",
+	)
+	assert.Equal(
+		t,
+		"The expected value is NaN but the actual value is not",
+		r.record[2].output[0].result.description,
+		"got expected description for a NaN expected value",
+	)
+}
+
+func numericApproxInfinities(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(math.Inf(-1), r.Approx(math.Inf(-1), 1), "same infinities")
+	r.Passes(math.Inf(1), r.Approx(math.Inf(-1), math.MaxFloat64), "infinities of different signs")
+	r.Passes(math.Inf(1), r.Approx(1.0, math.Inf(1)), "infinite tolerance")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 3, "three states were recorded")
+	assert.True(t, r.record[0].output[0].result.pass, "infinities with the same sign are equal")
+	for _, i := range []int{1, 2} {
+		res := r.record[i].output[0].result
+		assert.Equal(t, inValue, res.where, "failure is in the value")
+		assert.Equal(
+			t,
+			"An infinite value is only close to an infinite value with the same sign",
+			res.description,
+			"got expected description",
+		)
+	}
+	assert.Equal(t, "within ±+Inf", r.record[2].output[0].result.op, "op shows the infinite tolerance")
+}
+
+func numericApproxLargeIntegers(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(int64(1<<53+1), r.Approx(int64(1<<53), 0), "int64 values above 2^53")
+	r.Passes(uint64(1<<63+1), r.Approx(int64(math.MaxInt64), 1), "large uint64 and int64")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 2, "two states were recorded")
+	assert.Equal(
+		t,
+		"The values differ by 1, which is more than the tolerance of 0",
+		r.record[0].output[0].result.description,
+		"integers bigger than 2^53 are not compared as floats",
+	)
+	assert.Equal(
+		t,
+		"The values differ by 2, which is more than the tolerance of 1",
+		r.record[1].output[0].result.description,
+		"a uint64 bigger than any int64 is compared exactly",
+	)
+}
+
+func numericApproxUsageErrors(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(1.0, r.Approx(1.0, -1), "negative tolerance")
+	r.Passes(1.0, r.Approx(nil, 0.1), "nil expected")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 2, "two states were recorded")
+
+	res := r.record[0].output[0].result
+	assert.Equal(t, inUsage, res.where, "negative tolerance is a usage error")
+	assert.Equal(t, "within ±-1", res.op, "op shows the tolerance")
+	assert.Equal(
+		t,
+		"The tolerance passed to detest.(*D).Approx() must be a positive number or zero, but you passed -1",
+		res.description,
+		"got expected description for a negative tolerance",
+	)
+
+	res = r.record[1].output[0].result
+	assert.Equal(t, inUsage, res.where, "non-numeric expected value is a usage error")
+	assert.Equal(
+		t,
+		"You passed a nil to detest.(*D).Approx() but it needs an integer or float",
+		res.description,
+		"got expected description for a nil expected value",
+	)
+}
+
+func numericApproxTypeErrors(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes("1.0", r.Approx(1.0, 0.1), "string actual")
+	r.Passes(complex(1, 0), r.Approx(1.0, 0.1), "complex actual")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 2, "two states were recorded")
+	assert.Equal(t, inType, r.record[0].output[0].result.where, "string actual is a type error")
+	assert.Equal(
+		t,
+		"Called detest.(*D).Approx() but the value being tested isn't an integer or float, it's a string",
+		r.record[0].output[0].result.description,
+		"got expected description for a string",
+	)
+	assert.Equal(
+		t,
+		"Called detest.(*D).Approx() but the value being tested isn't an integer or float, it's a complex128",
+		r.record[1].output[0].result.description,
+		"complex numbers are not accepted",
+	)
+}
+
+func numericWithinPercentPasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(105, d.WithinPercent(100, 5), "ints")
+	d.Passes(-95.0, d.WithinPercent(-100.0, 5), "negative numbers")
+	d.Passes(0, d.WithinPercent(0.0, 1), "zeros")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: ints\n")
+}
+
+func numericWithinPercentFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(110, r.WithinPercent(100, 5), "outside tolerance")
+	r.Passes(0.001, r.WithinPercent(0, 50), "expected zero")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 2, "two states were recorded")
+	assert.Equal(t, "within 5%", r.record[0].output[0].result.op, "op shows the percentage")
+	assert.Equal(
+		t,
+		"The values differ by 10% of the expected value, which is more than the tolerance of 5%",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+	assert.Equal(
+		t,
+		"The expected value is 0, so the actual value must be 0 as well",
+		r.record[1].output[0].result.description,
+		"got expected description when the expected value is 0",
+	)
+}
+
+func numericWithinULPPasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(0.3, d.WithinULP(0.3, 0), "same value")
+	d.Passes(0.1+0.2, d.WithinULP(0.3, 1), "arithmetic")
+	d.Passes(math.Copysign(0, -1), d.WithinULP(0.0, 0), "zeros of different signs")
+	d.Passes(
+		math.Copysign(math.SmallestNonzeroFloat64, -1),
+		d.WithinULP(math.SmallestNonzeroFloat64, 2),
+		"across zero",
+	)
+	d.Passes(float32(3), d.WithinULP(3, 0), "float32 and int")
+	d.Passes(math.Nextafter32(1, 2), d.WithinULP(float32(1), 1), "float32")
+	d.Passes(float32(1<<24), d.WithinULP(1<<24+1, 0), "values that are equal as float32")
+	d.Passes(int64(1<<53+1), d.WithinULP(int64(1<<53), 1), "integers that convert to the same float")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: same value\n")
+}
+
+func numericWithinULPFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(math.Nextafter(math.Nextafter(1, 2), 2), r.WithinULP(1.0, 1), "outside tolerance")
+	r.Passes(float32(0.1), r.WithinULP(0.1, 1), "float32 and float64")
+	r.Passes(int64(1<<53+1), r.WithinULP(int64(1<<53), 0), "integers that convert to the same float")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 3, "three states were recorded")
+	assert.Equal(t, "within 1 ULP", r.record[0].output[0].result.op, "op shows the ULP tolerance")
+	assert.Equal(
+		t,
+		"The values are 2 ULP apart, which is more than the tolerance of 1 ULP",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+	assert.Equal(
+		t,
+		"The values are 107374182 ULP apart, which is more than the tolerance of 1 ULP",
+		r.record[1].output[0].result.description,
+		"a float32 is measured in float64 ULP when compared to a float64",
+	)
+	assert.Equal(
+		t,
+		"The values differ by 1 but they convert to the same float64, so they cannot be told apart in ULP",
+		r.record[2].output[0].result.description,
+		"integers that differ are not equal even when their floats are",
+	)
+}
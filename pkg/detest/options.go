@@ -0,0 +1,73 @@
+package detest
+
+import (
+	"reflect"
+)
+
+// EqualOption is an option which changes how `d.Equal` and `d.ValueEqual`
+// compare two values.
+type EqualOption func(*equalOptions)
+
+type equalOptions struct {
+	ignoreFields     map[string]bool
+	ignoreUnexported bool
+	ignoreTypes      map[reflect.Type]bool
+}
+
+func newEqualOptions(opts []EqualOption) equalOptions {
+	eo := equalOptions{
+		ignoreFields: map[string]bool{},
+		ignoreTypes:  map[reflect.Type]bool{},
+	}
+	for _, o := range opts {
+		o(&eo)
+	}
+	return eo
+}
+
+// IgnoreFields returns an option which tells the comparison to skip any
+// struct field with one of the given names, no matter where it is in the data
+// structure.
+func IgnoreFields(names ...string) EqualOption {
+	return func(eo *equalOptions) {
+		for _, n := range names {
+			eo.ignoreFields[n] = true
+		}
+	}
+}
+
+// IgnoreUnexported returns an option which tells the comparison to skip all
+// unexported struct fields.
+func IgnoreUnexported() EqualOption {
+	return func(eo *equalOptions) {
+		eo.ignoreUnexported = true
+	}
+}
+
+// IgnoreTypes returns an option which tells the comparison to skip any value
+// with the same type as one of the given values, no matter where it is in the
+// data structure. For example, `IgnoreTypes(sync.Mutex{})` skips all mutexes.
+func IgnoreTypes(values ...interface{}) EqualOption {
+	return func(eo *equalOptions) {
+		for _, v := range values {
+			eo.ignoreTypes[reflect.TypeOf(v)] = true
+		}
+	}
+}
+
+// ignoresField returns true if the given field should be skipped, either
+// because of a comparison option or because it has a `detest:"-"` or
+// `detest:"ignore"` struct tag.
+func (eo equalOptions) ignoresField(f reflect.StructField) bool {
+	if tag := f.Tag.Get("detest"); tag == "-" || tag == "ignore" {
+		return true
+	}
+	if eo.ignoreUnexported && f.PkgPath != "" {
+		return true
+	}
+	return eo.ignoreFields[f.Name]
+}
+
+func (eo equalOptions) ignoresType(ty reflect.Type) bool {
+	return eo.ignoreTypes[ty]
+}
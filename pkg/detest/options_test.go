@@ -0,0 +1,145 @@
+package detest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqualOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"IgnoreFields", optionsIgnoreFields},
+		{"IgnoreFields in nested structs", optionsIgnoreFieldsInNestedStructs},
+		{"IgnoreUnexported", optionsIgnoreUnexported},
+		{"IgnoreTypes", optionsIgnoreTypes},
+		{"ValueEqual accepts options", optionsValueEqual},
+		{"Struct tags", optionsStructTags},
+		{"Skipped fields are shown on failure", optionsSkippedFieldsAreShownOnFailure},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+type record struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
+	mu        sync.Mutex
+	Children  []record
+}
+
+func optionsIgnoreFields(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		record{ID: 1, Name: "foo", CreatedAt: time.Now()},
+		d.Equal(record{ID: 2, Name: "foo"}, IgnoreFields("ID", "CreatedAt")),
+		"ignore fields",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: ignore fields\n")
+}
+
+func optionsIgnoreFieldsInNestedStructs(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		record{Name: "foo", Children: []record{{ID: 1, Name: "bar"}}},
+		d.Equal(record{Name: "foo", Children: []record{{ID: 2, Name: "bar"}}}, IgnoreFields("ID")),
+		"ignore nested fields",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+}
+
+func optionsIgnoreUnexported(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		private{name: "foo"},
+		d.Equal(private{name: "bar"}, IgnoreUnexported()),
+		"ignore unexported fields",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+}
+
+func optionsIgnoreTypes(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	locked := &record{Name: "foo"}
+	locked.mu.Lock()
+	defer locked.mu.Unlock()
+	d.Is(
+		locked,
+		d.Equal(&record{Name: "foo"}, IgnoreTypes(sync.Mutex{})),
+		"ignore mutex",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+}
+
+func optionsValueEqual(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		map[string]interface{}{"a": record{ID: 1, Name: "foo"}},
+		d.ValueEqual(map[string]record{"a": {ID: 2, Name: "foo"}}, IgnoreFields("ID")),
+		"value equal with options",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+}
+
+type tagged struct {
+	Name    string
+	Ignored int `detest:"ignore"`
+	Dash    int `detest:"-"`
+}
+
+func optionsStructTags(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		tagged{Name: "foo", Ignored: 1, Dash: 2},
+		tagged{Name: "foo", Ignored: 3, Dash: 4},
+		"ignore tagged fields",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+}
+
+func optionsSkippedFieldsAreShownOnFailure(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]record{{ID: 1, Name: "foo"}},
+		r.Equal([]record{{ID: 2, Name: "bar"}}, IgnoreFields("ID"), IgnoreTypes(sync.Mutex{})),
+		"skipped fields",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[]record", "[0]", ".Name"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		[]string{"[0].ID", "[0].mu"},
+		r.record[0].output[0].result.skipped,
+		"got expected skipped fields",
+	)
+
+	call := mockT.FindCall("WriteString")
+	assert.NotNil(t, call, "WriteString was called")
+	assert.Contains(t, call.Args[0], "Skipped: [0].ID, [0].mu", "output includes skipped fields")
+}
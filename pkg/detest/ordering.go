@@ -0,0 +1,235 @@
+package detest
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// OrderComparer implements comparison of values which have an ordering, like
+// numbers, strings, and times.
+type OrderComparer struct {
+	expect interface{}
+	op     string
+	called string
+}
+
+// GT takes an expected value and returns an OrderComparer which passes if the
+// actual value is greater than the expected value.
+//
+// The two values can be any integer or float types, strings, `time.Time`
+// values, or `time.Duration` values. Numbers of different types are compared
+// by value, so an `int8` can be compared to a `float64`.
+func (d *D) GT(expect interface{}) OrderComparer {
+	return OrderComparer{expect, ">", "detest.(*D).GT"}
+}
+
+// GTE takes an expected value and returns an OrderComparer which passes if
+// the actual value is greater than or equal to the expected value. It accepts
+// the same types as `d.GT`.
+func (d *D) GTE(expect interface{}) OrderComparer {
+	return OrderComparer{expect, ">=", "detest.(*D).GTE"}
+}
+
+// LT takes an expected value and returns an OrderComparer which passes if the
+// actual value is less than the expected value. It accepts the same types as
+// `d.GT`.
+func (d *D) LT(expect interface{}) OrderComparer {
+	return OrderComparer{expect, "<", "detest.(*D).LT"}
+}
+
+// LTE takes an expected value and returns an OrderComparer which passes if
+// the actual value is less than or equal to the expected value. It accepts
+// the same types as `d.GT`.
+func (d *D) LTE(expect interface{}) OrderComparer {
+	return OrderComparer{expect, "<=", "detest.(*D).LTE"}
+}
+
+// Compare compares the value in d.Actual() to the expected value passed to
+// GT(), GTE(), LT(), or LTE().
+func (oc OrderComparer) Compare(d *D) {
+	actual := d.Actual()
+	actualVal := reflect.ValueOf(actual)
+	d.PushPath(d.NewPath(describeTypeOfReflectValue(actualVal), 1, oc.called))
+	defer d.PopPath()
+
+	result := result{
+		actual: newValue(actual),
+		expect: newValue(oc.expect),
+		op:     oc.op,
+	}
+
+	cmp, where, desc := compareOrder(actualVal, reflect.ValueOf(oc.expect))
+	if desc != "" {
+		result.where = where
+		result.description = desc
+		d.AddResult(result)
+		return
+	}
+
+	switch oc.op {
+	case ">":
+		result.pass = cmp > 0
+	case ">=":
+		result.pass = cmp >= 0
+	case "<":
+		result.pass = cmp < 0
+	case "<=":
+		result.pass = cmp <= 0
+	}
+	if !result.pass {
+		result.where = inValue
+	}
+
+	d.AddResult(result)
+}
+
+// BetweenComparer implements a check that a value is in a closed range.
+type BetweenComparer struct {
+	lo interface{}
+	hi interface{}
+}
+
+// Between takes two values and returns a BetweenComparer which passes if the
+// actual value is greater than or equal to `lo` and less than or equal to
+// `hi`. It accepts the same types as `d.GT`.
+func (d *D) Between(lo, hi interface{}) BetweenComparer {
+	return BetweenComparer{lo, hi}
+}
+
+// Compare checks that the value in d.Actual() is between the two values
+// passed to Between().
+func (bc BetweenComparer) Compare(d *D) {
+	actual := d.Actual()
+	actualVal := reflect.ValueOf(actual)
+	d.PushPath(d.NewPath(describeTypeOfReflectValue(actualVal), 1, "detest.(*D).Between"))
+	defer d.PopPath()
+
+	result := result{
+		actual: newValue(actual),
+		op:     fmt.Sprintf("in [%v, %v]", bc.lo, bc.hi),
+	}
+
+	loVal, hiVal := reflect.ValueOf(bc.lo), reflect.ValueOf(bc.hi)
+	if cmp, _, desc := compareOrder(loVal, hiVal); desc == "" && cmp > 0 {
+		result.where = inUsage
+		result.description = fmt.Sprintf(
+			"The low value passed to detest.(*D).Between() (%v) is greater than the high value (%v)",
+			bc.lo, bc.hi,
+		)
+		d.AddResult(result)
+		return
+	}
+
+	cmpLo, where, desc := compareOrder(actualVal, loVal)
+	if desc == "" {
+		var cmpHi int
+		cmpHi, where, desc = compareOrder(actualVal, hiVal)
+		result.pass = cmpLo >= 0 && cmpHi <= 0
+	}
+	if desc != "" {
+		result.where = where
+		result.description = desc
+	} else if !result.pass {
+		result.where = inValue
+	}
+
+	d.AddResult(result)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// compareOrder returns -1, 0, or 1 depending on whether the actual value is
+// less than, equal to, or greater than the expected value. If the two values
+// cannot be ordered, it returns a non-empty description of the problem and
+// where the problem is.
+func compareOrder(actual, expect reflect.Value) (int, failure, string) {
+	if actual.IsValid() && expect.IsValid() {
+		switch {
+		case actual.Type() == timeType && expect.Type() == timeType:
+			return compareTimes(actual.Interface().(time.Time), expect.Interface().(time.Time)), inValue, ""
+		case actual.Kind() == reflect.String && expect.Kind() == reflect.String:
+			return strings.Compare(actual.String(), expect.String()), inValue, ""
+		case isRealNumber(actual) && isRealNumber(expect):
+			cmp, ok := compareNumbers(actual, expect)
+			if !ok {
+				return 0, inValue, "NaN cannot be ordered relative to any other number"
+			}
+			return cmp, inValue, ""
+		}
+	}
+
+	return 0, inType, fmt.Sprintf(
+		"Cannot order %s relative to %s",
+		articleize(describeTypeOfReflectValue(actual)),
+		articleize(describeTypeOfReflectValue(expect)),
+	)
+}
+
+func compareTimes(actual, expect time.Time) int {
+	switch {
+	case actual.Before(expect):
+		return -1
+	case actual.After(expect):
+		return 1
+	}
+	return 0
+}
+
+// compareNumbers orders two integers or floats. It returns false if either
+// value is NaN.
+func compareNumbers(actual, expect reflect.Value) (int, bool) {
+	// A negative int is less than any uint, and converting it to a uint would
+	// overflow, so we handle mixed signedness before converting anything.
+	switch {
+	case actual.CanInt() && expect.CanUint():
+		if actual.Int() < 0 {
+			return -1, true
+		}
+		return compareUints(uint64(actual.Int()), expect.Uint()), true
+	case actual.CanUint() && expect.CanInt():
+		if expect.Int() < 0 {
+			return 1, true
+		}
+		return compareUints(actual.Uint(), uint64(expect.Int())), true
+	}
+
+	actual, expect, _ = safelyConvertNumberTypes(actual, expect, isNumeric(actual), isNumeric(expect))
+
+	switch {
+	case actual.CanInt():
+		a, e := actual.Int(), expect.Int()
+		switch {
+		case a < e:
+			return -1, true
+		case a > e:
+			return 1, true
+		}
+		return 0, true
+	case actual.CanUint():
+		return compareUints(actual.Uint(), expect.Uint()), true
+	}
+
+	a, e := actual.Float(), expect.Float()
+	switch {
+	case math.IsNaN(a) || math.IsNaN(e):
+		return 0, false
+	case a < e:
+		return -1, true
+	case a > e:
+		return 1, true
+	}
+	return 0, true
+}
+
+func compareUints(actual, expect uint64) int {
+	switch {
+	case actual < expect:
+		return -1
+	case actual > expect:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,199 @@
+package detest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrdering(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"Passing numeric comparisons", orderingNumbersPass},
+		{"Failing numeric comparisons", orderingNumbersFail},
+		{"NaN cannot be ordered", orderingNaN},
+		{"Strings, times, and durations", orderingStringsAndTimes},
+		{"Values of different types", orderingDifferentTypes},
+		{"Between passes", orderingBetweenPasses},
+		{"Between fails", orderingBetweenFails},
+		{"Between with a reversed range", orderingBetweenReversedRange},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+func orderingNumbersPass(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(4, d.GT(3), "ints")
+	d.Passes(4, d.GT(3.5), "int and float")
+	d.Passes(uint8(0), d.GT(int64(-1)), "uint and negative int")
+	d.Passes(uint64(math.MaxUint64), d.GT(math.MaxInt64), "large uint and int")
+	d.Passes(int64(1<<53+1), d.GT(int64(1<<53)), "int64 values above 2^53")
+	d.Passes(3, d.GTE(uint16(3)), "equal values")
+	d.Passes(int8(-4), d.LT(uint16(2)), "negative int and uint")
+	d.Passes(float32(1.5), d.LTE(1.5), "floats")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: ints\n")
+}
+
+func orderingNumbersFail(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		map[string]int{"retries": 2},
+		r.Map(func(mt *MapTester) {
+			mt.Key("retries", r.GTE(3))
+			mt.End()
+		}),
+		"at least 3 retries",
+	)
+	r.Passes(3, r.GT(3), "equal values")
+	r.Passes(uint8(0), r.LT(-1), "uint and negative int")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 3, "three states were recorded")
+	assert.Equal(
+		t,
+		&result{
+			actual: &value{value: 2, desc: "int"},
+			expect: &value{value: 3, desc: "int"},
+			op:     ">=",
+			pass:   false,
+			path: []Path{
+				{
+					data:   "map[string]int",
+					callee: "detest.(*D).Map",
+					caller: "detest.(*DetestRecorder).Is",
+				},
+				{
+					data:   "[retries]",
+					callee: "detest.(*MapTester).Key",
+					caller: "detest.orderingNumbersFail.func1",
+				},
+				{
+					data:   "int",
+					callee: "detest.(*D).GTE",
+					caller: "detest.orderingNumbersFail.func1",
+				},
+			},
+			where: inValue,
+		},
+		r.record[0].output[0].result,
+		"got the expected result",
+	)
+	assert.False(t, r.record[1].output[0].result.pass, "3 is not greater than 3")
+	assert.Equal(t, ">", r.record[1].output[0].result.op, "got expected op")
+	assert.False(t, r.record[2].output[0].result.pass, "a uint is never less than a negative int")
+	assert.Equal(t, inValue, r.record[2].output[0].result.where, "failure is in the value")
+}
+
+func orderingNaN(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(math.NaN(), r.GT(1), "NaN")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inValue, res.where, "failure is in the value")
+	assert.Equal(t, "NaN cannot be ordered relative to any other number", res.description, "got expected description")
+}
+
+func orderingStringsAndTimes(t *testing.T) {
+	now := time.Now()
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes("apple", r.LT("banana"), "strings")
+	r.Passes(now.Add(time.Second), r.GT(now), "times")
+	r.Passes(150*time.Millisecond, r.LT(200*time.Millisecond), "durations")
+	r.Passes("b", r.LTE("a"), "strings out of order")
+	r.Passes(250*time.Millisecond, r.LT(200*time.Millisecond), "durations out of order")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 5, "five states were recorded")
+	for i, pass := range []bool{true, true, true, false, false} {
+		assert.Equal(t, pass, r.record[i].output[0].result.pass, "result %d", i)
+	}
+	assert.Equal(t, "<=", r.record[3].output[0].result.op, "got expected op")
+}
+
+func orderingDifferentTypes(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes("4", r.GT(3), "string and int")
+	r.Passes(time.Time{}, r.LT(time.Second), "time and duration")
+	r.Passes(complex(1, 1), r.LT(complex(2, 2)), "complex numbers")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 3, "three states were recorded")
+	for i, desc := range []string{
+		"Cannot order a string relative to an int",
+		"Cannot order a Time relative to a Duration",
+		"Cannot order a complex128 relative to a complex128",
+	} {
+		res := r.record[i].output[0].result
+		assert.Equal(t, inType, res.where, "failure %d is a type error", i)
+		assert.Equal(t, desc, res.description, "got expected description for failure %d", i)
+	}
+}
+
+func orderingBetweenPasses(t *testing.T) {
+	now := time.Now()
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(3, d.Between(1, 3), "ints")
+	d.Passes(uint8(2), d.Between(-1, 2.5), "mixed numbers")
+	d.Passes("b", d.Between("a", "c"), "strings")
+	d.Passes(now, d.Between(now.Add(-time.Hour), now), "times")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: ints\n")
+}
+
+func orderingBetweenFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(0, r.Between(1, 3), "below range")
+	r.Passes(3.5, r.Between(1, 3), "above range")
+	r.Passes("2", r.Between(1, 3), "wrong type")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 3, "three states were recorded")
+	for i, where := range []failure{inValue, inValue, inType} {
+		res := r.record[i].output[0].result
+		assert.Equal(t, "in [1, 3]", res.op, "op shows the range for failure %d", i)
+		assert.Equal(t, where, res.where, "got expected where for failure %d", i)
+	}
+	assert.Equal(
+		t,
+		"Cannot order a string relative to an int",
+		r.record[2].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func orderingBetweenReversedRange(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(2, r.Between(3, 1), "reversed range")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inUsage, res.where, "failure is a usage error")
+	assert.Equal(t, "in [3, 1]", res.op, "got expected op")
+	assert.Equal(
+		t,
+		"The low value passed to detest.(*D).Between() (3) is greater than the high value (1)",
+		res.description,
+		"got expected description",
+	)
+}
@@ -0,0 +1,129 @@
+package detest
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Panics tests that calling the given function panics. The value recovered
+// from the panic is then tested with `expect`, which can be a literal value
+// or a `Comparer`. If `expect` is nil then any panic passes.
+//
+// The final arguments follow the same rules as `d.Is`.
+func (d *D) Panics(fn func(), expect interface{}, args ...interface{}) bool {
+	d.ResetState()
+	d.PushPath(d.NewPath("panic", 0, ""))
+	defer d.PopPath()
+
+	recovered, panicked, _ := catchPanic(fn)
+	if !panicked {
+		d.AddResult(noPanicResult())
+		return d.ok(argsToName(args, "unnamed d.Panics call"))
+	}
+
+	d.PushActual(recovered)
+	defer d.PopActual()
+
+	switch e := expect.(type) {
+	case nil:
+		d.AddResult(result{
+			actual: newValue(recovered),
+			op:     "panics",
+			pass:   true,
+		})
+	case Comparer:
+		e.Compare(d)
+	default:
+		d.Equal(expect).Compare(d)
+	}
+	return d.ok(argsToName(args, "unnamed d.Panics call"))
+}
+
+// PanicsWithError tests that calling the given function panics with an
+// error. The error is then tested with the given comparer, which will
+// usually be one of the error comparers like `d.ErrorIs`.
+//
+// The final arguments follow the same rules as `d.Is`.
+func (d *D) PanicsWithError(fn func(), expect Comparer, args ...interface{}) bool {
+	d.ResetState()
+	d.PushPath(d.NewPath("panic", 0, ""))
+	defer d.PopPath()
+
+	recovered, panicked, _ := catchPanic(fn)
+	if !panicked {
+		d.AddResult(noPanicResult())
+		return d.ok(argsToName(args, "unnamed d.PanicsWithError call"))
+	}
+
+	err, ok := recovered.(error)
+	if !ok {
+		d.AddResult(result{
+			actual: newValue(recovered),
+			op:     "panics",
+			pass:   false,
+			where:  inType,
+			description: fmt.Sprintf(
+				"The function panicked with %s, not an error",
+				articleize(describeTypeOfActualValue(recovered)),
+			),
+		})
+		return d.ok(argsToName(args, "unnamed d.PanicsWithError call"))
+	}
+
+	d.PushActual(err)
+	defer d.PopActual()
+
+	expect.Compare(d)
+	return d.ok(argsToName(args, "unnamed d.PanicsWithError call"))
+}
+
+// NotPanics tests that calling the given function does not panic. If it
+// does, the failure includes the value recovered from the panic and the stack
+// of the goroutine where the panic happened.
+//
+// The final arguments follow the same rules as `d.Is`.
+func (d *D) NotPanics(fn func(), args ...interface{}) bool {
+	d.ResetState()
+	d.PushPath(d.NewPath("panic", 0, ""))
+	defer d.PopPath()
+
+	recovered, panicked, stack := catchPanic(fn)
+	result := result{
+		op:   "does not panic",
+		pass: !panicked,
+	}
+	if panicked {
+		result.actual = newValue(recovered)
+		result.where = inValue
+		result.description = fmt.Sprintf("The function panicked with %v\n\n%s", recovered, stack)
+	}
+	d.AddResult(result)
+
+	return d.ok(argsToName(args, "unnamed d.NotPanics call"))
+}
+
+func noPanicResult() result {
+	return result{
+		op:          "panics",
+		pass:        false,
+		where:       inValue,
+		description: "The function did not panic",
+	}
+}
+
+// catchPanic calls fn and recovers from any panic. It returns the recovered
+// value, whether fn panicked, and the stack of the goroutine at the point of
+// the panic. This works even if fn calls `panic(nil)`.
+func catchPanic(fn func()) (recovered interface{}, panicked bool, stack []byte) {
+	panicked = true
+	defer func() {
+		if panicked {
+			recovered = recover()
+			stack = debug.Stack()
+		}
+	}()
+
+	fn()
+	panicked = false
+	return recovered, panicked, stack
+}
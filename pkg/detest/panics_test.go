@@ -0,0 +1,124 @@
+package detest
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanics(t *testing.T) {
+	t.Run("Passing assertions", panicsPassingTests)
+	t.Run("Panics without a panic", panicsNoPanic)
+	t.Run("Panics with the wrong value", panicsWrongValue)
+	t.Run("PanicsWithError with a non-error", panicsWithErrorNonError)
+	t.Run("NotPanics fails", panicsNotPanicsFails)
+}
+
+func panicsPassingTests(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name   string
+		assert func(d *D, name string) bool
+	}{
+		{"Panics with a value", func(d *D, name string) bool {
+			return d.Panics(func() { panic("boom") }, "boom", name)
+		}},
+		{"Panics with a comparer", func(d *D, name string) bool {
+			return d.Panics(func() { panic("boom boom") }, d.HasPrefix("boom"), name)
+		}},
+		{"Panics with nil expect", func(d *D, name string) bool {
+			return d.Panics(func() { panic(42) }, nil, name)
+		}},
+		{"Panics with panic(nil)", func(d *D, name string) bool {
+			return d.Panics(func() { panic(nil) }, d.IsType(&runtime.PanicNilError{}), name)
+		}},
+		{"PanicsWithError", func(d *D, name string) bool {
+			return d.PanicsWithError(func() { panic(fmt.Errorf("wrapped: %w", errBoom)) }, d.ErrorIs(errBoom), name)
+		}},
+		{"NotPanics", func(d *D, name string) bool {
+			return d.NotPanics(func() {}, name)
+		}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mT := new(mockT)
+			d := NewWithOutput(mT, mT)
+			assert.True(t, test.assert(d, test.name), "assertion returned true")
+			mT.AssertNotCalled(t, "Fail")
+			mT.AssertCalled(t, "WriteString", fmt.Sprintf("Assertion ok: %s\n", test.name))
+		})
+	}
+}
+
+func panicsNoPanic(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.Panics(func() {}, nil, "no panic")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{{pass: false, dataPath: []string{"panic"}}},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, "The function did not panic", res.description, "got expected description")
+	assert.Equal(t, "detest.(*D).Panics", res.path[0].callee, "got expected callee")
+}
+
+func panicsWrongValue(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.Panics(func() { panic("boom") }, "bang", "wrong value")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{{pass: false, dataPath: []string{"panic", "string"}}},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, "boom", res.actual.value, "actual is the recovered value")
+	assert.Equal(t, "bang", res.expect.value, "got expected value")
+}
+
+func panicsWithErrorNonError(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.PanicsWithError(func() { panic(42) }, r.AnError(), "non-error")
+	mT.AssertCalled(t, "Fail")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inType, res.where, "failure is in the type")
+	assert.Equal(t, "The function panicked with an int, not an error", res.description, "got expected description")
+}
+
+func panicsNotPanicsFails(t *testing.T) {
+	mT := new(mockT)
+	d := NewWithOutput(mT, mT)
+	r := NewRecorder(d)
+	r.NotPanics(panicsExplode, "panics")
+	mT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{{pass: false, dataPath: []string{"panic"}}},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, "kaboom", res.actual.value, "actual is the recovered value")
+	assert.Contains(t, res.description, "The function panicked with kaboom\n\ngoroutine ", "description includes the stack")
+	assert.Contains(t, res.description, "detest.panicsExplode", "stack includes the panicking function")
+}
+
+func panicsExplode() {
+	panic("kaboom")
+}
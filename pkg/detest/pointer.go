@@ -0,0 +1,214 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DerefComparer implements comparison of the value a pointer points to.
+type DerefComparer struct {
+	expect interface{}
+}
+
+// Deref takes an expected value or a comparer and returns a DerefComparer
+// which checks the value that the actual pointer points to. A nil pointer is
+// considered a failure.
+func (d *D) Deref(expect interface{}) DerefComparer {
+	return DerefComparer{expect}
+}
+
+// Compare dereferences the pointer in d.Actual() and compares the value it
+// points to with the value or comparer passed to `Deref()`.
+func (dc DerefComparer) Compare(d *D) {
+	v := reflect.ValueOf(d.Actual())
+	d.PushPath(d.NewPath("*", 1, "detest.(*D).Deref"))
+	defer d.PopPath()
+
+	if !checkPointer(d, v, "Deref()") {
+		return
+	}
+
+	d.PushActual(v.Elem().Interface())
+	defer d.PopActual()
+
+	if c, ok := dc.expect.(Comparer); ok {
+		c.Compare(d)
+	} else {
+		d.Equal(dc.expect).Compare(d)
+	}
+}
+
+// PointerComparer implements comparison of pointer values.
+type PointerComparer struct {
+	with func(*PointerTester)
+}
+
+// Pointer takes a function which will be called to do further comparisons of
+// the pointer and the value it points to. A nil pointer is considered a
+// failure.
+func (d *D) Pointer(with func(*PointerTester)) PointerComparer {
+	return PointerComparer{with}
+}
+
+// PointerTester is the struct that will be passed to the test function passed
+// to detest.Pointer. This struct implements the pointer-specific testing
+// methods such as Value() and SamePointer().
+type PointerTester struct {
+	d *D
+}
+
+// Compare compares the pointer value in d.Actual() by calling the function
+// passed to `Pointer()`, which is in turn expected to further tests of the
+// pointer.
+func (pc PointerComparer) Compare(d *D) {
+	v := reflect.ValueOf(d.Actual())
+	d.PushPath(d.NewPath(describeTypeOfReflectValue(v), 1, "detest.(*D).Pointer"))
+	defer d.PopPath()
+
+	if !checkPointer(d, v, "Pointer()") {
+		return
+	}
+
+	pc.with(&PointerTester{d: d})
+}
+
+// Value takes an expected value or a comparer and compares it to the value
+// that the pointer points to.
+func (pt *PointerTester) Value(expect interface{}) {
+	pt.d.PushPath(pt.d.NewPath("*", 0, ""))
+	defer pt.d.PopPath()
+
+	pt.d.PushActual(reflect.ValueOf(pt.d.Actual()).Elem().Interface())
+	defer pt.d.PopActual()
+
+	if c, ok := expect.(Comparer); ok {
+		c.Compare(pt.d)
+	} else {
+		pt.d.Equal(expect).Compare(pt.d)
+	}
+}
+
+// SamePointer checks that the pointer being tested points to the same address
+// as the given pointer. Two pointers of different types are never the same.
+func (pt *PointerTester) SamePointer(expect interface{}) {
+	actual := reflect.ValueOf(pt.d.Actual())
+	e := reflect.ValueOf(expect)
+
+	if !e.IsValid() || e.Kind() != reflect.Ptr {
+		pt.d.AddResult(result{
+			actual: newValue(pt.d.Actual()),
+			pass:   false,
+			where:  inUsage,
+			description: fmt.Sprintf(
+				"You passed %s to SamePointer() but it needs a pointer",
+				articleize(describeTypeOfReflectValue(e)),
+			),
+		})
+		return
+	}
+
+	result := result{
+		actual: newValue(pt.d.Actual()),
+		expect: newValue(expect),
+		op:     "same pointer",
+		pass:   actual.Type() == e.Type() && actual.Pointer() == e.Pointer(),
+	}
+	if !result.pass {
+		result.where = inValue
+		if actual.Type() != e.Type() {
+			result.description = fmt.Sprintf(
+				"The pointer being tested is %s but the expected pointer is %s",
+				articleize(describeTypeOfReflectValue(actual)),
+				articleize(describeTypeOfReflectValue(e)),
+			)
+		} else {
+			result.description = fmt.Sprintf(
+				"The pointer being tested points to %#x but the expected pointer points to %#x",
+				actual.Pointer(), e.Pointer(),
+			)
+		}
+	}
+	pt.d.AddResult(result)
+}
+
+// AutoDeref turns automatic dereferencing on or off for this `*D`. When it is
+// on, `Slice`, `Array`, `Map`, and `Struct` follow pointers to the value being
+// tested, adding a `*` element to the path for each pointer. A nil pointer is
+// considered a failure.
+func (d *D) AutoDeref(on bool) {
+	d.autoDeref = on
+}
+
+// derefActual follows pointers from the value in d.Actual() when automatic
+// dereferencing is on. See derefPointers for details.
+func (d *D) derefActual(path Path, called string) (reflect.Value, func(), bool) {
+	if !d.autoDeref {
+		return reflect.ValueOf(d.Actual()), func() {}, true
+	}
+	return d.derefPointers(path, called)
+}
+
+// derefPointers follows pointers from the value in d.Actual(). Each
+// pointed-to value is pushed as the actual value, with a path element based
+// on the given path. The returned func undoes these pushes. If a nil pointer
+// is found this adds a failure and returns false.
+func (d *D) derefPointers(path Path, called string) (reflect.Value, func(), bool) {
+	v := reflect.ValueOf(d.Actual())
+	pushed := 0
+	undo := func() {
+		for i := 0; i < pushed; i++ {
+			d.PopActual()
+			d.PopPath()
+		}
+	}
+
+	for v.IsValid() && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			d.AddResult(nilPointerResult(d, called))
+			return v, undo, false
+		}
+
+		v = v.Elem()
+		d.PushPath(Path{data: "*", callee: path.callee, caller: path.caller})
+		d.PushActual(v.Interface())
+		pushed++
+	}
+
+	return v, undo, true
+}
+
+// checkPointer adds a failure and returns false if v is not a non-nil
+// pointer.
+func checkPointer(d *D, v reflect.Value, called string) bool {
+	if !v.IsValid() || v.Kind() != reflect.Ptr {
+		d.AddResult(result{
+			actual: newValue(d.Actual()),
+			pass:   false,
+			where:  inDataStructure,
+			op:     "*",
+			description: fmt.Sprintf(
+				"Called detest.%s but the value being tested isn't a pointer, it's %s",
+				called,
+				articleize(describeTypeOfReflectValue(v)),
+			),
+		})
+		return false
+	}
+
+	if v.IsNil() {
+		d.AddResult(nilPointerResult(d, called))
+		return false
+	}
+
+	return true
+}
+
+func nilPointerResult(d *D, called string) result {
+	return result{
+		actual:      newValue(d.Actual()),
+		pass:        false,
+		where:       inDataStructure,
+		op:          "*",
+		description: fmt.Sprintf("Called detest.%s but the pointer being tested is nil", called),
+	}
+}
@@ -0,0 +1,291 @@
+package detest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointer(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"Deref passes", pointerDerefPasses},
+		{"Deref with a comparer passes", pointerDerefSlicePasses},
+		{"Deref fails", pointerDerefFails},
+		{"Deref of non-pointer", pointerDerefNonPointer},
+		{"Deref of nil pointer", pointerDerefNil},
+		{"Pointer passes", pointerPointerPasses},
+		{"Pointer fails", pointerPointerFails},
+		{"SamePointer fails", pointerSamePointerFails},
+		{"SamePointer given a non-pointer", pointerSamePointerNonPointer},
+		{"AutoDeref", pointerAutoDeref},
+		{"AutoDeref with nil pointer", pointerAutoDerefNil},
+		{"AutoDeref off", pointerAutoDerefOff},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+func pointerDerefPasses(t *testing.T) {
+	n := 42
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(&n, d.Deref(42), "deref int")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: deref int\n")
+}
+
+func pointerDerefSlicePasses(t *testing.T) {
+	s := []int{1}
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(&s, d.Deref(d.Slice(func(st *SliceTester) {
+		st.Idx(0, 1)
+		st.End()
+	})), "deref slice")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: deref slice\n")
+}
+
+func pointerDerefFails(t *testing.T) {
+	s := []int{1}
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(&s, r.Deref(r.Slice(func(st *SliceTester) {
+		st.Idx(0, 2)
+		st.End()
+	})), "deref slice")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"*", "[]int", "[0]", "int"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(t, "detest.(*D).Deref", r.record[0].output[0].result.path[0].callee, "got expected callee")
+}
+
+func pointerDerefNonPointer(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(42, r.Deref(42), "deref int")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{{pass: false, dataPath: []string{"*"}}},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, inDataStructure, res.where, "failure is in the data structure")
+	assert.Equal(
+		t,
+		"Called detest.Deref() but the value being tested isn't a pointer, it's an int",
+		res.description,
+		"got expected description",
+	)
+}
+
+func pointerDerefNil(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is((*int)(nil), r.Deref(42), "deref nil")
+	mockT.AssertCalled(t, "Fail")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inDataStructure, res.where, "failure is in the data structure")
+	assert.Equal(
+		t,
+		"Called detest.Deref() but the pointer being tested is nil",
+		res.description,
+		"got expected description",
+	)
+}
+
+func pointerPointerPasses(t *testing.T) {
+	n := 42
+	p := &n
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(p, d.Pointer(func(pt *PointerTester) {
+		pt.SamePointer(&n)
+		pt.Value(d.GT(40))
+	}), "pointer")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: pointer\n")
+}
+
+func pointerPointerFails(t *testing.T) {
+	n := 42
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(&n, r.Pointer(func(pt *PointerTester) {
+		pt.Value(41)
+	}), "pointer")
+	mockT.AssertCalled(t, "Fail")
+	assert.Equal(
+		t,
+		[]Path{
+			{
+				data:   "*int",
+				callee: "detest.(*D).Pointer",
+				caller: "detest.(*DetestRecorder).Is",
+			},
+			{
+				data:   "*",
+				callee: "detest.(*PointerTester).Value",
+				caller: "detest.pointerPointerFails.func1",
+			},
+			{
+				data:   "int",
+				callee: "detest.(*D).Equal",
+				caller: "detest.pointerPointerFails.func1",
+			},
+		},
+		r.record[0].output[0].result.path,
+		"got expected path",
+	)
+}
+
+func pointerSamePointerFails(t *testing.T) {
+	a, b := 1, 1
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(&a, r.Pointer(func(pt *PointerTester) {
+		pt.SamePointer(&b)
+		pt.SamePointer(new(string))
+	}), "same pointer")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"*int"}},
+			{pass: false, dataPath: []string{"*int"}},
+		},
+		"got expected results",
+	)
+	assert.Contains(
+		t,
+		r.record[0].output[0].result.description,
+		"The pointer being tested points to 0x",
+		"got expected description for different addresses",
+	)
+	assert.Equal(
+		t,
+		"The pointer being tested is a *int but the expected pointer is a *string",
+		r.record[0].output[1].result.description,
+		"got expected description for different types",
+	)
+}
+
+func pointerSamePointerNonPointer(t *testing.T) {
+	n := 42
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(&n, r.Pointer(func(pt *PointerTester) {
+		pt.SamePointer(42)
+	}), "same pointer")
+	mockT.AssertCalled(t, "Fail")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inUsage, res.where, "failure is a usage error")
+	assert.Equal(
+		t,
+		"You passed an int to SamePointer() but it needs a pointer",
+		res.description,
+		"got expected description",
+	)
+}
+
+type pointerTestStruct struct {
+	Items *[]string
+}
+
+func pointerAutoDeref(t *testing.T) {
+	items := []string{"a", "b"}
+	s := &pointerTestStruct{Items: &items}
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.AutoDeref(true)
+	r := NewRecorder(d)
+	r.Is(&s, r.Struct(func(st *StructTester) {
+		st.Field("Items", r.Slice(func(sl *SliceTester) {
+			sl.Idx(0, "a")
+			sl.Idx(1, "c")
+			sl.End()
+		}))
+		st.End()
+	}), "auto deref")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"**pointerTestStruct", "*", "*", ".Items", "*[]string", "*", "[0]", "string"}},
+			{pass: false, dataPath: []string{"**pointerTestStruct", "*", "*", ".Items", "*[]string", "*", "[1]", "string"}},
+		},
+		"got expected results",
+	)
+}
+
+func pointerAutoDerefNil(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.AutoDeref(true)
+	r := NewRecorder(d)
+	r.Is((*map[string]int)(nil), r.Map(func(mt *MapTester) {
+		mt.End()
+	}), "auto deref")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{{pass: false, dataPath: []string{"*map[string]int"}}},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, inDataStructure, res.where, "failure is in the data structure")
+	assert.Equal(
+		t,
+		"Called detest.Map() but the pointer being tested is nil",
+		res.description,
+		"got expected description",
+	)
+}
+
+func pointerAutoDerefOff(t *testing.T) {
+	s := []int{1}
+
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(&s, r.Slice(func(st *SliceTester) {
+		st.End()
+	}), "no auto deref")
+	mockT.AssertCalled(t, "Fail")
+	assert.Equal(
+		t,
+		"Called detest.Slice() but the value being tested isn't a slice, it's a *[]int",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
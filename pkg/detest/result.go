@@ -5,6 +5,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/houseabsolute/detest/pkg/detest/internal/ansi"
 	"github.com/houseabsolute/detest/pkg/detest/internal/term"
@@ -28,6 +29,7 @@ type result struct {
 	path        []Path
 	where       failure
 	description string
+	skipped     []string
 }
 
 func newValue(val interface{}) *value {
@@ -46,15 +48,32 @@ func (r result) showExpect() bool {
 	return r.expect != nil
 }
 
+// isEquality returns true if the result is from comparing the actual value
+// to the expected value for equality. A diff or hex dump of the two values
+// is only meaningful for these results.
+func (r result) isEquality() bool {
+	return r.op == "==" || r.op == "== (value)"
+}
+
 type describer struct {
 	r  result
 	tw table.Writer
 	s  ansi.Scheme
+	// This is shown below the table in place of the actual and expected
+	// values when they're too big or complex to show in a table cell.
+	detail      string
+	placeholder string
 }
 
 func (r result) describe(name string, s ansi.Scheme) string {
 	tw := tableWithTitle(fmt.Sprintf("Assertion not ok: %s", name), s)
-	return describer{r, tw, s}.table()
+	d := describer{r: r, tw: tw, s: s}
+	if hd := r.hexDump(s); hd != "" {
+		d.detail, d.placeholder = hd, "<see hex dump below>"
+	} else if diff := r.unifiedDiff(s); diff != "" {
+		d.detail, d.placeholder = diff, "<see diff below>"
+	}
+	return d.table()
 }
 
 func (d describer) table() string {
@@ -94,12 +113,16 @@ func (d describer) table() string {
 		d.tw.SetColumnConfigs(cc)
 	}
 
+	if len(d.r.skipped) != 0 {
+		d.tw.SetCaption("Skipped: " + strings.Join(d.r.skipped, ", "))
+	}
+
 	var post string
 	if d.r.description != "" {
 		post = d.s.Strong(d.s.Incorrect(d.r.description)) + "\n"
 	}
 
-	return d.tw.Render() + "\n" + post
+	return d.tw.Render() + "\n" + d.detail + post
 }
 
 func (d describer) addHeaders() {
@@ -137,6 +160,14 @@ func (d describer) footer() ([]interface{}, map[string]int) {
 		expect = fmt.Sprintf("%v", d.r.expect.value)
 		widths["ACTUAL"] = displayWidth(actual)
 	}
+	// Multi-line strings and binary data are unreadable in a table cell, so
+	// we show them below the table instead.
+	if d.detail != "" {
+		actual = d.placeholder
+		expect = d.placeholder
+		widths["GOT"] = displayWidth(actual)
+		widths["ACTUAL"] = displayWidth(expect)
+	}
 	op = d.r.op
 
 	var aType, eType string
@@ -236,7 +267,8 @@ func describeType(ty reflect.Type) string {
 	case reflect.Func:
 		return describeFunc(ty)
 	case reflect.Interface:
-		// Can this happen?
+		// This happens for unnamed interface types like `interface{}`.
+		return ty.String()
 	case reflect.Map:
 		return fmt.Sprintf("map[%s]%s", describeType(ty.Key()), describeType(ty.Elem()))
 	case reflect.Ptr:
@@ -34,6 +34,30 @@ func (d *DetestRecorder) Passes(actual, expect interface{}, args ...interface{})
 	return ok
 }
 
+func (d *DetestRecorder) ValueIs(actual, expect interface{}, args ...interface{}) bool {
+	ok := d.D.ValueIs(actual, expect, args...)
+	d.record = append(d.record, d.D.state)
+	return ok
+}
+
+func (d *DetestRecorder) Panics(fn func(), expect interface{}, args ...interface{}) bool {
+	ok := d.D.Panics(fn, expect, args...)
+	d.record = append(d.record, d.D.state)
+	return ok
+}
+
+func (d *DetestRecorder) PanicsWithError(fn func(), expect Comparer, args ...interface{}) bool {
+	ok := d.D.PanicsWithError(fn, expect, args...)
+	d.record = append(d.record, d.D.state)
+	return ok
+}
+
+func (d *DetestRecorder) NotPanics(fn func(), args ...interface{}) bool {
+	ok := d.D.NotPanics(fn, args...)
+	d.record = append(d.record, d.D.state)
+	return ok
+}
+
 type Call struct {
 	Method string
 	Args   []interface{}
@@ -30,9 +30,16 @@ type SliceTester struct {
 // slice's content.
 func (sc SliceComparer) Compare(d *D) {
 	v := reflect.ValueOf(d.Actual())
-	d.PushPath(d.NewPath(describeTypeOfReflectValue(v), 1, "detest.(*D).Slice"))
+	path := d.NewPath(describeTypeOfReflectValue(v), 1, "detest.(*D).Slice")
+	d.PushPath(path)
 	defer d.PopPath()
 
+	v, undo, ok := d.derefActual(path, "Slice()")
+	defer undo()
+	if !ok {
+		return
+	}
+
 	if !v.IsValid() || v.Kind() != reflect.Slice {
 		d.AddResult(result{
 			actual: newValue(d.Actual()),
@@ -55,28 +62,21 @@ func (sc SliceComparer) Compare(d *D) {
 // Idx takes a slice index and an expected value for that index. If the index
 // is past the end of the array, this is considered a failure.
 func (st *SliceTester) Idx(idx int, expect interface{}) {
-	v := reflect.ValueOf(st.d.Actual())
-
 	st.d.PushPath(st.d.NewPath(fmt.Sprintf("[%d]", idx), 0, ""))
 	defer st.d.PopPath()
 
-	if idx >= v.Len() {
-		st.d.AddResult(result{
-			actual: newValue(st.d.Actual()),
-			pass:   false,
-			where:  inDataStructure,
-			op:     fmt.Sprintf("[%d]", idx),
-			description: fmt.Sprintf(
-				"Attempted to get an index (%d) past the end of a %d-element slice", idx, v.Len()),
-		})
+	elem, ok := elemAt(st.d, "slice", idx)
+	if !ok {
 		return
 	}
 
-	st.d.PushActual(v.Index(idx).Interface())
+	st.d.PushActual(elem)
 	defer st.d.PopActual()
 
 	st.seen[idx] = true
 
+	// The comparer must be called directly from this method so that the
+	// caller shown in its path is the test function.
 	if c, ok := expect.(Comparer); ok {
 		c.Compare(st.d)
 	} else {
@@ -92,14 +92,8 @@ func (st *SliceTester) AllValues(check interface{}) {
 	st.d.PushPath(st.d.NewPath("range", 0, ""))
 	defer st.d.PopPath()
 
-	comparer, err := st.d.FuncFor(check, "AllValues")
-	if err != nil {
-		st.d.AddResult(result{
-			actual:      newValue(st.d.Actual()),
-			pass:        false,
-			where:       inUsage,
-			description: err.Error(),
-		})
+	comparer, ok := allValuesComparer(st.d, check)
+	if !ok {
 		return
 	}
 
@@ -121,28 +115,72 @@ func (st *SliceTester) End() {
 }
 
 func (st *SliceTester) enforceEnding() {
+	enforceIdxEnding(st.d, "Slice()", "slice", st.ending, st.seen)
+}
+
+// elemAt returns the element at the given index of the slice or array in
+// d.Actual(). If the index is out of range it adds a failure and returns
+// false.
+func elemAt(d *D, noun string, idx int) (interface{}, bool) {
+	v := reflect.ValueOf(d.Actual())
+
+	if idx < 0 || idx >= v.Len() {
+		d.AddResult(result{
+			actual: newValue(d.Actual()),
+			pass:   false,
+			where:  inDataStructure,
+			op:     fmt.Sprintf("[%d]", idx),
+			description: fmt.Sprintf(
+				"Attempted to get an index (%d) past the end of a %d-element %s", idx, v.Len(), noun),
+		})
+		return nil, false
+	}
+
+	return v.Index(idx).Interface(), true
+}
+
+// allValuesComparer turns the check passed to AllValues into a
+// `FuncComparer`. If the check isn't a valid function it adds a failure and
+// returns false.
+func allValuesComparer(d *D, check interface{}) (FuncComparer, bool) {
+	comparer, err := d.FuncFor(check, "AllValues")
+	if err != nil {
+		d.AddResult(result{
+			actual:      newValue(d.Actual()),
+			pass:        false,
+			where:       inUsage,
+			description: err.Error(),
+		})
+		return FuncComparer{}, false
+	}
+	return comparer, true
+}
+
+// enforceIdxEnding checks that every index of the slice or array in
+// d.Actual() was tested when the tester's function called End().
+func enforceIdxEnding(d *D, called, noun string, ending CollectionEnding, seen map[int]bool) {
 	// If we got an error in anything but a value check that means the test
 	// aborted. This could mean attempting to get an index past the end of the
 	// slice, passing an incorrect type to AllValues, etc.
-	if st.d.lastResultIsNonValueError() {
+	if d.lastResultIsNonValueError() {
 		return
 	}
 
-	if st.ending == Etc {
+	if ending == Etc {
 		return
 	}
 
-	if st.ending == Unset {
-		st.d.AddWarning("The function passed to Slice() did not call Etc() or End()")
+	if ending == Unset {
+		d.AddWarning(fmt.Sprintf("The function passed to %s did not call Etc() or End()", called))
 		return
 	}
 
-	for i := 0; i < reflect.ValueOf(st.d.Actual()).Len(); i++ {
-		if !st.seen[i] {
-			st.d.AddResult(result{
+	for i := 0; i < reflect.ValueOf(d.Actual()).Len(); i++ {
+		if !seen[i] {
+			d.AddResult(result{
 				pass:        false,
 				where:       inUsage,
-				description: fmt.Sprintf("Your slice test did not check index %d", i),
+				description: fmt.Sprintf("Your %s test did not check index %d", noun, i),
 			})
 		}
 	}
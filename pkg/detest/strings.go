@@ -0,0 +1,184 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// StringComparer implements comparisons of strings, like checking for a
+// prefix or matching a regexp.
+type StringComparer struct {
+	expect  interface{}
+	op      string
+	called  string
+	matches func(actual string) (bool, string)
+}
+
+// Match takes a regexp and returns a StringComparer which passes if the
+// actual string matches the regexp.
+//
+// Like all of the string comparers, this accepts a `string`, a `[]byte`, or
+// anything that implements `fmt.Stringer` as the actual value.
+func (d *D) Match(re *regexp.Regexp) StringComparer {
+	return StringComparer{
+		expect: re.String(),
+		op:     "=~",
+		called: "detest.(*D).Match",
+		matches: func(actual string) (bool, string) {
+			if re.MatchString(actual) {
+				return true, ""
+			}
+			return false, fmt.Sprintf("The actual string does not match the regexp /%s/", re)
+		},
+	}
+}
+
+// HasPrefix takes a string and returns a StringComparer which passes if the
+// actual string starts with that string.
+func (d *D) HasPrefix(prefix string) StringComparer {
+	return StringComparer{
+		expect: prefix,
+		op:     "has prefix",
+		called: "detest.(*D).HasPrefix",
+		matches: func(actual string) (bool, string) {
+			return strings.HasPrefix(actual, prefix), ""
+		},
+	}
+}
+
+// HasSuffix takes a string and returns a StringComparer which passes if the
+// actual string ends with that string.
+func (d *D) HasSuffix(suffix string) StringComparer {
+	return StringComparer{
+		expect: suffix,
+		op:     "has suffix",
+		called: "detest.(*D).HasSuffix",
+		matches: func(actual string) (bool, string) {
+			return strings.HasSuffix(actual, suffix), ""
+		},
+	}
+}
+
+// Contains takes a string and returns a StringComparer which passes if the
+// actual string contains that string.
+func (d *D) Contains(substr string) StringComparer {
+	return StringComparer{
+		expect: substr,
+		op:     "contains",
+		called: "detest.(*D).Contains",
+		matches: func(actual string) (bool, string) {
+			return strings.Contains(actual, substr), ""
+		},
+	}
+}
+
+// EqualFold takes a string and returns a StringComparer which passes if the
+// actual string is equal to that string when compared case-insensitively.
+// This uses Unicode case folding, just like `strings.EqualFold`.
+func (d *D) EqualFold(expect string) StringComparer {
+	return StringComparer{
+		expect: expect,
+		op:     "== (fold case)",
+		called: "detest.(*D).EqualFold",
+		matches: func(actual string) (bool, string) {
+			return strings.EqualFold(actual, expect), ""
+		},
+	}
+}
+
+// EqualIgnoringWhitespace takes a string and returns a StringComparer which
+// passes if the actual string is equal to that string after normalizing the
+// whitespace in both. Normalizing trims leading and trailing whitespace and
+// replaces each run of whitespace with a single space.
+func (d *D) EqualIgnoringWhitespace(expect string) StringComparer {
+	normalizedExpect := normalizeWhitespace(expect)
+	return StringComparer{
+		expect: expect,
+		op:     "== (ignoring whitespace)",
+		called: "detest.(*D).EqualIgnoringWhitespace",
+		matches: func(actual string) (bool, string) {
+			normalizedActual := normalizeWhitespace(actual)
+			if normalizedActual == normalizedExpect {
+				return true, ""
+			}
+			return false, fmt.Sprintf(
+				"After normalizing whitespace, the actual string is %q and the expected string is %q",
+				normalizedActual, normalizedExpect,
+			)
+		},
+	}
+}
+
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Compare checks the string in d.Actual() using the comparison returned by
+// Match(), HasPrefix(), HasSuffix(), Contains(), EqualFold(), or
+// EqualIgnoringWhitespace().
+func (sc StringComparer) Compare(d *D) {
+	actual := d.Actual()
+	v := reflect.ValueOf(actual)
+	d.PushPath(d.NewPath(describeTypeOfReflectValue(v), 1, sc.called))
+	defer d.PopPath()
+
+	result := result{
+		actual: newValue(actual),
+		expect: newValue(sc.expect),
+		op:     sc.op,
+	}
+
+	str, ok := stringFromValue(v)
+	if !ok {
+		got := articleize(describeTypeOfReflectValue(v))
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			got = "a nil " + describeTypeOfReflectValue(v)
+		}
+		result.where = inType
+		result.description = fmt.Sprintf(
+			"Called %s() but the value being tested isn't a string, []byte, or fmt.Stringer, it's %s",
+			sc.called,
+			got,
+		)
+		d.AddResult(result)
+		return
+	}
+
+	// If the actual value was a []byte or Stringer, we show the string that
+	// we compared instead of the original value.
+	result.actual = newValue(str)
+	result.pass, result.description = sc.matches(str)
+	if !result.pass {
+		result.where = inValue
+	}
+
+	d.AddResult(result)
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// stringFromValue returns the string in a value if it is a string, a byte
+// slice, or a `fmt.Stringer`. A string type with a `String` method is treated
+// as a plain string.
+func stringFromValue(v reflect.Value) (string, bool) {
+	if !v.IsValid() {
+		return "", false
+	}
+
+	switch {
+	case v.Kind() == reflect.String:
+		return v.String(), true
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return string(v.Bytes()), true
+	case v.Type().Implements(stringerType):
+		// Calling String on a nil pointer will probably panic.
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return "", false
+		}
+		return v.Interface().(fmt.Stringer).String(), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,183 @@
+package detest
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"Match passes", stringsMatchPasses},
+		{"Match fails", stringsMatchFails},
+		{"Prefix, suffix, and substring passes", stringsAffixesPass},
+		{"Prefix, suffix, and substring fails", stringsAffixesFail},
+		{"Case and whitespace insensitive passes", stringsInsensitivePasses},
+		{"Case and whitespace insensitive fails", stringsInsensitiveFails},
+		{"Value is not a string", stringsNotAString},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+type label struct {
+	name string
+}
+
+func (l *label) String() string {
+	return "label:" + l.name
+}
+
+func stringsMatchPasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes("foo123", d.Match(regexp.MustCompile(`^foo\d+$`)), "string")
+	d.Passes([]byte("foo123"), d.Match(regexp.MustCompile(`\d`)), "[]byte")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: string\n")
+}
+
+func stringsMatchFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes("foo", r.Match(regexp.MustCompile(`^\d+$`)), "no digits")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inValue, res.where, "failure is in the value")
+	assert.Equal(t, "=~", res.op, "got expected op")
+	assert.Equal(t, `^\d+$`, res.expect.value, "expect is the regexp")
+	assert.Equal(
+		t,
+		`The actual string does not match the regexp /^\d+$/`,
+		res.description,
+		"got expected description",
+	)
+}
+
+func stringsAffixesPass(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes("foobar", d.HasPrefix("foo"), "HasPrefix")
+	d.Passes(stringish("foobar"), d.HasPrefix("foo"), "HasPrefix with string type")
+	d.Passes(&label{"x"}, d.HasSuffix(":x"), "HasSuffix with Stringer")
+	d.Passes(1500*time.Millisecond, d.Contains("1.5s"), "Contains with Stringer")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: HasPrefix\n")
+}
+
+func stringsAffixesFail(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		[]string{"foo"},
+		r.Slice(func(st *SliceTester) {
+			st.Idx(0, r.HasPrefix("bar"))
+			st.End()
+		}),
+		"slice of strings",
+	)
+	r.Passes([]byte("foobar"), r.HasSuffix("foo"), "HasSuffix with []byte")
+	r.Passes("foobar", r.Contains("baz"), "Contains")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 3, "three states were recorded")
+	assert.Equal(
+		t,
+		&result{
+			actual: &value{value: "foo", desc: "string"},
+			expect: &value{value: "bar", desc: "string"},
+			op:     "has prefix",
+			pass:   false,
+			path: []Path{
+				{
+					data:   "[]string",
+					callee: "detest.(*D).Slice",
+					caller: "detest.(*DetestRecorder).Is",
+				},
+				{
+					data:   "[0]",
+					callee: "detest.(*SliceTester).Idx",
+					caller: "detest.stringsAffixesFail.func1",
+				},
+				{
+					data:   "string",
+					callee: "detest.(*D).HasPrefix",
+					caller: "detest.stringsAffixesFail.func1",
+				},
+			},
+			where: inValue,
+		},
+		r.record[0].output[0].result,
+		"got the expected result",
+	)
+	assert.Equal(t, "has suffix", r.record[1].output[0].result.op, "got expected op for HasSuffix")
+	assert.Equal(t, "contains", r.record[2].output[0].result.op, "got expected op for Contains")
+}
+
+func stringsInsensitivePasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes("Straße", d.EqualFold("STRAßE"), "EqualFold")
+	d.Passes(
+		"  select *\n\tfrom   foo ",
+		d.EqualIgnoringWhitespace("select * from foo"),
+		"EqualIgnoringWhitespace",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: EqualFold\n")
+}
+
+func stringsInsensitiveFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes("foo", r.EqualFold("FOOD"), "EqualFold")
+	r.Passes("a  b\nc", r.EqualIgnoringWhitespace("a bc"), "EqualIgnoringWhitespace")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 2, "two states were recorded")
+
+	res := r.record[0].output[0].result
+	assert.Equal(t, inValue, res.where, "failure is in the value")
+	assert.Equal(t, "== (fold case)", res.op, "got expected op for EqualFold")
+
+	res = r.record[1].output[0].result
+	assert.Equal(t, "== (ignoring whitespace)", res.op, "got expected op for EqualIgnoringWhitespace")
+	assert.Equal(
+		t,
+		`After normalizing whitespace, the actual string is "a b c" and the expected string is "a bc"`,
+		res.description,
+		"got expected description",
+	)
+}
+
+func stringsNotAString(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(42, r.Contains("4"), "int")
+	r.Passes((*label)(nil), r.Contains("x"), "nil Stringer")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 2, "two states were recorded")
+	assert.Equal(t, inType, r.record[0].output[0].result.where, "failure is a type error")
+	assert.Equal(
+		t,
+		"Called detest.(*D).Contains() but the value being tested isn't a string, []byte, or fmt.Stringer, it's an int",
+		r.record[0].output[0].result.description,
+		"got expected description for an int",
+	)
+	assert.Equal(
+		t,
+		"Called detest.(*D).Contains() but the value being tested isn't a string, []byte, or fmt.Stringer, it's a nil *label",
+		r.record[1].output[0].result.description,
+		"a nil Stringer is not called",
+	)
+}
@@ -3,12 +3,16 @@ package detest
 import (
 	"fmt"
 	"reflect"
-	"unsafe"
+	"strings"
 )
 
 // StructComparer implements comparison of struct values.
 type StructComparer struct {
 	with func(*StructTester)
+	// If this is true then pointers to the struct are always followed, even
+	// when automatic dereferencing is off. This is used by `StructOf` when
+	// its type is a struct pointer.
+	derefPointers bool
 }
 
 // Struct takes a function which will be called to do further comparisons of
@@ -16,14 +20,17 @@ type StructComparer struct {
 // method if you want to access private fields with the StructComparer.Field()
 // method.
 func (d *D) Struct(with func(*StructTester)) StructComparer {
-	return StructComparer{with}
+	return StructComparer{with: with}
 }
 
 // StructTester is the struct that will be passed to the test function passed
 // to detest.Struct. This struct implements the struct-specific testing methods
 // such as Idx() and AllValues().
 type StructTester struct {
-	d *D
+	d          *D
+	ending     CollectionEnding
+	unexported bool
+	seen       map[string]bool
 }
 
 // Compare compares the struct value in d.Actual() by calling the function
@@ -32,9 +39,20 @@ type StructTester struct {
 func (sc StructComparer) Compare(d *D) {
 	v := reflect.ValueOf(d.Actual())
 
-	d.PushPath(d.NewPath(describeTypeOfReflectValue(v), 1, "detest.(*D).Struct"))
+	path := d.NewPath(describeTypeOfReflectValue(v), 1, "detest.(*D).Struct")
+	d.PushPath(path)
 	defer d.PopPath()
 
+	deref := d.derefActual
+	if sc.derefPointers {
+		deref = d.derefPointers
+	}
+	v, undo, ok := deref(path, "Struct()")
+	defer undo()
+	if !ok {
+		return
+	}
+
 	if !v.IsValid() ||
 		v.Kind() != reflect.Struct ||
 		(v.Kind() == reflect.Ptr &&
@@ -52,48 +70,298 @@ func (sc StructComparer) Compare(d *D) {
 		return
 	}
 
-	st := &StructTester{d: d}
+	st := &StructTester{d: d, seen: map[string]bool{}}
+	defer st.enforceEnding()
 	sc.with(st)
 }
 
 // Field takes a field name and an expected value for that field. If the field
 // does not exist, this is considered a failure.
+//
+// The name can be a dotted path like "Address.City" to reach into a nested
+// struct, in which case each field along the way is a separate element of the
+// path in the output. Pointers to structs along the way are dereferenced, and
+// a nil pointer is considered a failure. Fields promoted from embedded structs
+// can be accessed by their own name or through the embedded type's name, like
+// "Base.ID".
+//
+// When checking for a call to `End()`, a dotted path counts as a check of the
+// first field in the path, and a promoted field counts as a check of the
+// embedded field it was promoted from.
 func (st *StructTester) Field(field string, expect interface{}) {
-	v := reflect.ValueOf(st.d.Actual())
+	d := st.d
+
+	v := reflect.ValueOf(d.Actual())
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	} else {
+		v = addressable(v)
+	}
+
+	names := strings.Split(field, ".")
+	for i, name := range names {
+		d.PushPath(d.NewPath(fmt.Sprintf(".%v", name), 0, ""))
+		defer d.PopPath()
+
+		f, top, desc := fieldByName(v, name)
+		if desc != "" {
+			actual := d.Actual()
+			if i > 0 {
+				actual = v.Interface()
+			}
+			d.AddResult(result{
+				actual:      newValue(actual),
+				pass:        false,
+				where:       inDataStructure,
+				op:          fmt.Sprintf(".%s", name),
+				description: desc,
+			})
+			return
+		}
+
+		if i == 0 {
+			st.seen[top] = true
+		}
+		v = f
+
+		if i == len(names)-1 {
+			break
+		}
+
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				d.AddResult(result{
+					actual: newValue(v.Interface()),
+					pass:   false,
+					where:  inDataStructure,
+					op:     fmt.Sprintf(".%s", names[i+1]),
+					description: fmt.Sprintf(
+						"Attempted to get the %s field through %s, which is a nil pointer",
+						names[i+1], name,
+					),
+				})
+				return
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			d.AddResult(result{
+				actual: newValue(v.Interface()),
+				pass:   false,
+				where:  inDataStructure,
+				op:     fmt.Sprintf(".%s", names[i+1]),
+				description: fmt.Sprintf(
+					"Attempted to get the %s field of %s, which isn't a struct, it's %s",
+					names[i+1], name, articleize(describeTypeOfReflectValue(v)),
+				),
+			})
+			return
+		}
+	}
+
+	d.PushActual(v.Interface())
+	defer d.PopActual()
+
+	if c, ok := expect.(Comparer); ok {
+		c.Compare(d)
+	} else {
+		d.Equal(expect).Compare(d)
+	}
+}
 
-	st.d.PushPath(st.d.NewPath(fmt.Sprintf(".%v", field), 0, ""))
-	defer st.d.PopPath()
+// fieldByName returns the named field of the given struct, which must be
+// addressable. If the field is promoted from an embedded struct, this follows
+// each embedded field in turn, dereferencing embedded pointers. It also
+// returns the name of the struct's own field that the value was found under.
+// If the field cannot be found it returns a description of the problem.
+func fieldByName(v reflect.Value, name string) (reflect.Value, string, string) {
+	sf, ok := v.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}, "", "Attempted to get a struct field that does not exist"
+	}
+
+	top := v.Type().Field(sf.Index[0]).Name
+	embedded := ""
+	for _, i := range sf.Index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, top, fmt.Sprintf(
+					"Attempted to get the %s field, which is promoted from the embedded %s field, but %s is a nil pointer",
+					name, embedded, embedded,
+				)
+			}
+			v = v.Elem()
+		}
+		embedded = v.Type().Field(i).Name
+		v = field(v, i)
+	}
+
+	return v, top, ""
+}
+
+// Method takes a method name and an expected value for each value that the
+// method returns. The method is called with no arguments on the struct, or on
+// a pointer to it if the method has a pointer receiver. Each expected value
+// can be a literal value or a `Comparer`.
+//
+// If the method does not exist, takes arguments, or returns a different
+// number of values than the number of expected values you passed, this is
+// considered a usage error.
+func (st *StructTester) Method(name string, expect ...interface{}) {
+	d := st.d
+	d.PushPath(d.NewPath(fmt.Sprintf(".%s()", name), 0, ""))
+	defer d.PopPath()
 
-	// This is hack to be able to get private fields from structs (as opposed
-	// to struct pointers, where this is a little simpler). We need to copy
-	// the original Value into an addressable Value.
-	v2 := v
-	if v.Kind() == reflect.Struct {
-		v2 = reflect.New(v.Type()).Elem()
-		v2.Set(v)
+	ret, ok := st.callMethod(name, nil, len(expect), "Method()")
+	if !ok {
+		return
 	}
 
-	f := v2.FieldByName(field)
-	if f.IsValid() {
-		f = reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+	st.compareReturns(ret, expect)
+}
+
+// MethodWith is like Method, but it takes a slice of arguments to pass to the
+// method. The arguments are checked against the method's signature in the
+// same way as the arguments passed to `d.Call`.
+func (st *StructTester) MethodWith(name string, args []interface{}, expect ...interface{}) {
+	d := st.d
+	d.PushPath(d.NewPath(fmt.Sprintf(".%s()", name), 0, ""))
+	defer d.PopPath()
+
+	ret, ok := st.callMethod(name, args, len(expect), "MethodWith()")
+	if !ok {
+		return
 	}
-	if !f.IsValid() {
-		st.d.AddResult(result{
-			actual:      newValue(st.d.Actual()),
+
+	st.compareReturns(ret, expect)
+}
+
+// compareReturns compares each value returned by a method to the matching
+// expected value. When the method returns more than one value, each
+// comparison gets its own path element.
+func (st *StructTester) compareReturns(ret []reflect.Value, expect []interface{}) {
+	d := st.d
+	for i, e := range expect {
+		if len(ret) > 1 {
+			d.PushPath(d.NewPath(fmt.Sprintf("→[%d]", i), 1, ""))
+		}
+		d.PushActual(ret[i].Interface())
+		if c, ok := e.(Comparer); ok {
+			c.Compare(d)
+		} else {
+			d.Equal(e).Compare(d)
+		}
+		d.PopActual()
+		if len(ret) > 1 {
+			d.PopPath()
+		}
+	}
+}
+
+// callMethod finds the named method, checks that it can be called with the
+// given arguments and that it returns the expected number of values, and then
+// calls it. If any of this fails it adds a usage failure and returns false.
+func (st *StructTester) callMethod(name string, args []interface{}, returns int, called string) ([]reflect.Value, bool) {
+	d := st.d
+
+	usage := func(desc string) ([]reflect.Value, bool) {
+		d.AddResult(result{
+			actual:      newValue(d.Actual()),
 			pass:        false,
-			where:       inDataStructure,
-			op:          fmt.Sprintf(".%s", field),
-			description: "Attempted to get a struct field that does not exist",
+			where:       inUsage,
+			op:          fmt.Sprintf(".%s()", name),
+			description: desc,
 		})
+		return nil, false
+	}
+
+	v := reflect.ValueOf(d.Actual())
+	if v.Kind() != reflect.Ptr {
+		v = addressable(v).Addr()
+	}
+
+	m := v.MethodByName(name)
+	if !m.IsValid() {
+		return usage(fmt.Sprintf(
+			"Called %s but %s does not have an exported method named %s",
+			called, describeTypeOfReflectValue(v.Elem()), name,
+		))
+	}
+
+	t := m.Type()
+	if err := checkNumArgs(t, len(args), fmt.Sprintf("the %s method", name)); err != nil {
+		if called == "Method()" {
+			return usage(err.Error() + ". Method() cannot pass any arguments, so use MethodWith() instead")
+		}
+		return usage(err.Error())
+	}
+
+	in, err := argumentValues(t, args, called, fmt.Sprintf("the %s method", name))
+	if err != nil {
+		return usage(err.Error())
+	}
+
+	if t.NumOut() != returns {
+		return usage(fmt.Sprintf(
+			"%s was passed %d expected value(s) but the %s method returns %d",
+			called, returns, name, t.NumOut(),
+		))
+	}
+
+	return m.Call(in), true
+}
+
+// Etc means that not all fields of the struct will be tested.
+func (st *StructTester) Etc() {
+	st.ending = Etc
+}
+
+// End means that all exported fields of the struct must be tested or else
+// the test will fail. Fields with a `detest:"-"` or `detest:"ignore"` tag do
+// not need to be tested.
+func (st *StructTester) End() {
+	st.ending = End
+}
+
+// EndAll is like End, but unexported fields must be tested as well.
+func (st *StructTester) EndAll() {
+	st.ending = End
+	st.unexported = true
+}
+
+func (st *StructTester) enforceEnding() {
+	// If we got an error in anything but a value check that means the test
+	// aborted. This could mean attempting to get a field that doesn't exist.
+	if st.d.lastResultIsNonValueError() {
 		return
 	}
 
-	st.d.PushActual(f.Interface())
-	defer st.d.PopActual()
+	if st.ending == Etc {
+		return
+	}
 
-	if c, ok := expect.(Comparer); ok {
-		c.Compare(st.d)
-	} else {
-		st.d.Equal(expect).Compare(st.d)
+	if st.ending == Unset {
+		st.d.AddWarning("The function passed to Struct() did not call Etc() or End()")
+		return
+	}
+
+	ty := reflect.TypeOf(st.d.Actual())
+	if ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+
+	var noOpts equalOptions
+	for i := 0; i < ty.NumField(); i++ {
+		f := ty.Field(i)
+		if (f.PkgPath != "" && !st.unexported) || noOpts.ignoresField(f) {
+			continue
+		}
+		if !st.seen[f.Name] {
+			st.d.AddResult(result{
+				pass:        false,
+				where:       inUsage,
+				description: fmt.Sprintf("Your struct test did not check field %s", f.Name),
+			})
+		}
 	}
 }
@@ -1,6 +1,7 @@
 package detest
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,24 @@ func TestStruct(t *testing.T) {
 		{"Passed non-struct to Struct", structPassedNonStruct},
 		{"Passed nil to Struct", structPassedNil},
 		{"Field called that does not exist in the struct", structFieldCalledThatDoesNotExist},
+		{"No call to Etc or End", structNoCallToEtcOrEnd},
+		{"Calls End but does not check all exported fields", structCallsEndButDoesNotCheckAllFields},
+		{"Calls EndAll but does not check unexported fields", structCallsEndAllButDoesNotCheckUnexported},
+		{"Calls Etc and does not check all fields", structCallsEtcAndDoesNotCheckAllFields},
+		{"Dotted field path", structDottedFieldPath},
+		{"Dotted field path through a nil pointer", structDottedFieldPathThroughNilPointer},
+		{"Dotted field path through a non-struct", structDottedFieldPathThroughNonStruct},
+		{"Promoted field", structPromotedField},
+		{"Promoted field through a nil embedded pointer", structPromotedFieldThroughNilEmbeddedPointer},
+		{"Method", structMethod},
+		{"MethodWith", structMethodWith},
+		{"Method that does not exist", structMethodDoesNotExist},
+		{"Method that takes arguments", structMethodTakesArguments},
+		{"MethodWith wrong number of arguments", structMethodWithWrongNumberOfArguments},
+		{"MethodWith nil arguments", structMethodWithNilArguments},
+		{"MethodWith too few arguments for a variadic method", structMethodWithTooFewVariadicArguments},
+		{"MethodWith wrong argument type", structMethodWithWrongArgumentType},
+		{"Method with wrong number of expected values", structMethodWrongNumberOfExpectedValues},
 	}
 
 	for _, test := range tests {
@@ -37,6 +56,7 @@ func structPassingTest(t *testing.T) {
 		d.Struct(func(st *StructTester) {
 			st.Field("foo", "x")
 			st.Field("bar", nil)
+			st.End()
 		}),
 		"s.foo == x && s.bar == nil",
 	)
@@ -52,6 +72,7 @@ func structFailingTest(t *testing.T) {
 		s{foo: "x"},
 		r.Struct(func(st *StructTester) {
 			st.Field("foo", "y")
+			st.Etc()
 		}),
 		"s.foo == y",
 	)
@@ -99,6 +120,7 @@ func structMixPassingAndFailingTests(t *testing.T) {
 		r.Struct(func(st *StructTester) {
 			st.Field("foo", "y")
 			st.Field("bar", nil)
+			st.End()
 		}),
 		"s.foo == y && s.bar == nil",
 	)
@@ -151,6 +173,7 @@ func structPassedNonStruct(t *testing.T) {
 		42,
 		r.Struct(func(st *StructTester) {
 			st.Field("foo", "x")
+			st.End()
 		}),
 		"non-struct",
 	)
@@ -185,6 +208,7 @@ func structPassedNil(t *testing.T) {
 		nil,
 		r.Struct(func(st *StructTester) {
 			st.Field("foo", "x")
+			st.End()
 		}),
 		"non-struct",
 	)
@@ -219,6 +243,7 @@ func structFieldCalledThatDoesNotExist(t *testing.T) {
 		s{foo: "x", bar: []int{1}},
 		r.Struct(func(st *StructTester) {
 			st.Field("what", 42)
+			st.End()
 		}),
 		"does not exist in struct",
 	)
@@ -251,3 +276,437 @@ func structFieldCalledThatDoesNotExist(t *testing.T) {
 		"got the expected result",
 	)
 }
+
+type structEnding struct {
+	Name    string
+	Age     int
+	Ignored bool `detest:"-"`
+	secret  string
+}
+
+func structNoCallToEtcOrEnd(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		structEnding{Name: "x"},
+		r.Struct(func(st *StructTester) {
+			st.Field("Name", "x")
+		}),
+		"no Etc or End",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 2, "record has state with two output items")
+	assert.Equal(
+		t,
+		"The function passed to Struct() did not call Etc() or End()",
+		r.record[0].output[1].warning,
+		"got the expected warning",
+	)
+}
+
+func structCallsEndButDoesNotCheckAllFields(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		structEnding{Name: "x"},
+		r.Struct(func(st *StructTester) {
+			st.Field("Name", "x")
+			st.End()
+		}),
+		"End",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"structEnding", ".Name", "string"}},
+			{pass: false, dataPath: []string{"structEnding"}},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[1].result
+	assert.Equal(t, inUsage, res.where, "failure is a usage error")
+	assert.Equal(t, "Your struct test did not check field Age", res.description, "got expected description")
+}
+
+func structCallsEndAllButDoesNotCheckUnexported(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		structEnding{Name: "x"},
+		r.Struct(func(st *StructTester) {
+			st.Field("Name", "x")
+			st.Field("Age", 0)
+			st.EndAll()
+		}),
+		"EndAll",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 3, "record has state with three output items")
+	assert.Equal(
+		t,
+		"Your struct test did not check field secret",
+		r.record[0].output[2].result.description,
+		"got expected description",
+	)
+}
+
+func structCallsEtcAndDoesNotCheckAllFields(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Is(
+		structEnding{Name: "x"},
+		d.Struct(func(st *StructTester) {
+			st.Field("Name", "x")
+			st.Etc()
+		}),
+		"Etc",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: Etc\n")
+}
+
+type structAddress struct {
+	City string
+}
+
+type structBase struct {
+	ID int
+}
+
+type structPerson struct {
+	*structBase
+	Name    string
+	Home    structAddress
+	Work    *structAddress
+	Aliases []string
+}
+
+func structDottedFieldPath(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		structPerson{Home: structAddress{City: "Minneapolis"}, Work: &structAddress{City: "St. Paul"}},
+		r.Struct(func(st *StructTester) {
+			st.Field("Home.City", "Minneapolis")
+			st.Field("Work.City", "Duluth")
+			st.Etc()
+		}),
+		"dotted paths",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"structPerson", ".Home", ".City", "string"}},
+			{pass: false, dataPath: []string{"structPerson", ".Work", ".City", "string"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"detest.structDottedFieldPath.func1",
+		r.record[0].output[0].result.path[2].caller,
+		"each hop in the path has the test function as its caller",
+	)
+}
+
+func structDottedFieldPathThroughNilPointer(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		structPerson{},
+		r.Struct(func(st *StructTester) {
+			st.Field("Work.City", "Duluth")
+			st.End()
+		}),
+		"nil pointer",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"structPerson", ".Work"}},
+		},
+		"got expected results",
+	)
+	res := r.record[0].output[0].result
+	assert.Equal(t, inDataStructure, res.where, "failure is in the data structure")
+	assert.Equal(t, ".City", res.op, "op is the field we tried to get")
+	assert.Equal(
+		t,
+		"Attempted to get the City field through Work, which is a nil pointer",
+		res.description,
+		"got expected description",
+	)
+}
+
+func structDottedFieldPathThroughNonStruct(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		structPerson{},
+		r.Struct(func(st *StructTester) {
+			st.Field("Name.Length", 0)
+			st.End()
+		}),
+		"non-struct",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	assert.Equal(
+		t,
+		"Attempted to get the Length field of Name, which isn't a struct, it's a string",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func structPromotedField(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		structPerson{structBase: &structBase{ID: 42}, Work: &structAddress{}},
+		r.Struct(func(st *StructTester) {
+			st.Field("ID", 42)
+			st.Field("structBase.ID", 42)
+			st.Field("Name", "")
+			st.Field("Home", structAddress{})
+			st.Field("Work.City", "")
+			st.Field("Aliases", nil)
+			st.End()
+		}),
+		"promoted field",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output[:2],
+		[]resultExpect{
+			{pass: true, dataPath: []string{"structPerson", ".ID", "int"}},
+			{pass: true, dataPath: []string{"structPerson", ".structBase", ".ID", "int"}},
+		},
+		"got expected results",
+	)
+}
+
+func structPromotedFieldThroughNilEmbeddedPointer(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		structPerson{},
+		r.Struct(func(st *StructTester) {
+			st.Field("ID", 42)
+			st.Etc()
+		}),
+		"nil embedded pointer",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: false, dataPath: []string{"structPerson", ".ID"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Attempted to get the ID field, which is promoted from the embedded structBase field, but structBase is a nil pointer",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+type structGetters struct {
+	first string
+	last  string
+	attrs map[string]string
+}
+
+func (sg structGetters) FullName() string {
+	return sg.first + " " + sg.last
+}
+
+func (sg *structGetters) Get(key string) (string, bool) {
+	v, ok := sg.attrs[key]
+	return v, ok
+}
+
+func (sg structGetters) Join(sep string, keys ...string) string {
+	vals := make([]string, len(keys))
+	for i, k := range keys {
+		vals[i] = sg.attrs[k]
+	}
+	return strings.Join(vals, sep)
+}
+
+func structMethod(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		structGetters{first: "Jane", last: "Doe"},
+		r.Struct(func(st *StructTester) {
+			st.Method("FullName", "Jane Doe")
+			st.Method("FullName", r.HasPrefix("John"))
+			st.Etc()
+		}),
+		"Method",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"structGetters", ".FullName()", "string"}},
+			{pass: false, dataPath: []string{"structGetters", ".FullName()", "string"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"detest.structMethod.func1",
+		r.record[0].output[0].result.path[1].caller,
+		"method path has the test function as its caller",
+	)
+}
+
+func structMethodWith(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(
+		structGetters{attrs: map[string]string{"color": "blue"}},
+		r.Struct(func(st *StructTester) {
+			st.MethodWith("Get", []interface{}{"color"}, "blue", true)
+			st.MethodWith("Get", []interface{}{"size"}, "", true)
+			st.Etc()
+		}),
+		"MethodWith",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"structGetters", ".Get()", "→[0]", "string"}},
+			{pass: true, dataPath: []string{"structGetters", ".Get()", "→[1]", "bool"}},
+			{pass: true, dataPath: []string{"structGetters", ".Get()", "→[0]", "string"}},
+			{pass: false, dataPath: []string{"structGetters", ".Get()", "→[1]", "bool"}},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		Path{
+			data:   "→[1]",
+			callee: "detest.(*StructTester).MethodWith",
+			caller: "detest.structMethodWith.func1",
+		},
+		r.record[0].output[3].result.path[2],
+		"return value path has the test function as its caller",
+	)
+}
+
+func structMethodUsageError(t *testing.T, with func(*StructTester), expect string) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Is(structGetters{}, r.Struct(with), "usage error")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inUsage, res.where, "failure is a usage error")
+	assert.Equal(t, expect, res.description, "got expected description")
+}
+
+func structMethodDoesNotExist(t *testing.T) {
+	structMethodUsageError(
+		t,
+		func(st *StructTester) {
+			st.Method("NickName", "JD")
+			st.Etc()
+		},
+		"Called Method() but structGetters does not have an exported method named NickName",
+	)
+}
+
+func structMethodTakesArguments(t *testing.T) {
+	structMethodUsageError(
+		t,
+		func(st *StructTester) {
+			st.Method("Get", "blue", true)
+			st.Etc()
+		},
+		"the Get method takes 1 value, but you passed 0. Method() cannot pass any arguments, so use MethodWith() instead",
+	)
+}
+
+func structMethodWithWrongNumberOfArguments(t *testing.T) {
+	structMethodUsageError(
+		t,
+		func(st *StructTester) {
+			st.MethodWith("Get", []interface{}{"a", "b"}, "blue", true)
+			st.Etc()
+		},
+		"the Get method takes 1 value, but you passed 2",
+	)
+}
+
+func structMethodWithNilArguments(t *testing.T) {
+	structMethodUsageError(
+		t,
+		func(st *StructTester) {
+			st.MethodWith("Get", nil, "blue", true)
+			st.Etc()
+		},
+		"the Get method takes 1 value, but you passed 0",
+	)
+}
+
+func structMethodWithTooFewVariadicArguments(t *testing.T) {
+	structMethodUsageError(
+		t,
+		func(st *StructTester) {
+			st.MethodWith("Join", nil, "")
+			st.Etc()
+		},
+		"the Join method takes at least 1 value, but you passed 0",
+	)
+}
+
+func structMethodWithWrongArgumentType(t *testing.T) {
+	structMethodUsageError(
+		t,
+		func(st *StructTester) {
+			st.MethodWith("Get", []interface{}{42}, "blue", true)
+			st.Etc()
+		},
+		"argument 0 passed to MethodWith() is an int but the Get method takes a string",
+	)
+}
+
+func structMethodWrongNumberOfExpectedValues(t *testing.T) {
+	structMethodUsageError(
+		t,
+		func(st *StructTester) {
+			st.MethodWith("Get", []interface{}{"color"}, "blue")
+			st.Etc()
+		},
+		"MethodWith() was passed 1 expected value(s) but the Get method returns 2",
+	)
+}
@@ -0,0 +1,315 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// This file contains a typed layer on top of the `interface{}`-based API. Each
+// of these functions and methods is a thin wrapper around the untyped
+// equivalent, so the test output is identical, but the compiler can catch
+// things like passing a `func(string) bool` to `AllValues` for a `[]int`.
+
+// TypedComparer is a `Comparer` which only accepts values of type `T`. Use
+// `Typed` to turn any other `Comparer` into one.
+type TypedComparer[T any] interface {
+	Comparer
+	accepts(T)
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// IsT works like `d.Is` except that the actual and expected values must be
+// of the same type.
+func IsT[T any](d *D, actual, expect T, args ...interface{}) bool {
+	return d.Is(actual, expect, args...)
+}
+
+// PassesT works like `d.Passes` except that the comparer must accept the
+// type of the actual value.
+func PassesT[T any](d *D, actual T, expect TypedComparer[T], args ...interface{}) bool {
+	return d.Passes(actual, expect, args...)
+}
+
+// checkTyped adds a failed result and returns false if the value being
+// tested is not of the type that a typed comparer requires.
+func checkTyped(d *D, want reflect.Type, called string) bool {
+	v := reflect.ValueOf(d.Actual())
+	if v.IsValid() && v.Type() == want {
+		return true
+	}
+
+	d.PushPath(d.NewPath(describeTypeOfReflectValue(v), 2, called))
+	defer d.PopPath()
+
+	d.AddResult(result{
+		actual: newValue(d.Actual()),
+		pass:   false,
+		where:  inType,
+		description: fmt.Sprintf(
+			"Called %s() but the value being tested isn't %s, it's %s",
+			called,
+			articleize(describeType(want)),
+			articleize(describeTypeOfReflectValue(v)),
+		),
+	})
+
+	return false
+}
+
+// TypedSliceComparer implements comparison of `[]T` values.
+type TypedSliceComparer[T any] struct {
+	with func(*TypedSliceTester[T])
+}
+
+// SliceOf takes a function which will be called to do further comparisons of
+// the contents of a `[]T`.
+func SliceOf[T any](with func(*TypedSliceTester[T])) TypedSliceComparer[T] {
+	return TypedSliceComparer[T]{with}
+}
+
+// TypedSliceTester is the struct that will be passed to the test function
+// passed to `detest.SliceOf`.
+type TypedSliceTester[T any] struct {
+	st *SliceTester
+}
+
+func (TypedSliceComparer[T]) accepts([]T) {}
+
+// Compare checks that the value in d.Actual() is a `[]T` and then compares it
+// the same way as `SliceComparer.Compare`.
+func (sc TypedSliceComparer[T]) Compare(d *D) {
+	if !checkTyped(d, typeOf[[]T](), "detest.SliceOf") {
+		return
+	}
+	d.Slice(func(st *SliceTester) {
+		sc.with(&TypedSliceTester[T]{st})
+	}).Compare(d)
+}
+
+// Idx takes a slice index and an expected value for that index.
+func (tst *TypedSliceTester[T]) Idx(idx int, expect T) {
+	tst.st.Idx(idx, expect)
+}
+
+// IdxPasses takes a slice index and a comparer for the value at that index.
+func (tst *TypedSliceTester[T]) IdxPasses(idx int, expect TypedComparer[T]) {
+	tst.st.Idx(idx, expect)
+}
+
+// AllValues passes every slice value to the given function in turn.
+func (tst *TypedSliceTester[T]) AllValues(check func(T) bool) {
+	tst.st.AllValues(check)
+}
+
+// Etc means that not all elements of the slice will be tested.
+func (tst *TypedSliceTester[T]) Etc() {
+	tst.st.Etc()
+}
+
+// End means that all elements of the slice must be tested or else the test
+// will fail.
+func (tst *TypedSliceTester[T]) End() {
+	tst.st.End()
+}
+
+// TypedMapComparer implements comparison of `map[K]V` values.
+type TypedMapComparer[K comparable, V any] struct {
+	with func(*TypedMapTester[K, V])
+}
+
+// MapOf takes a function which will be called to do further comparisons of
+// the contents of a `map[K]V`.
+func MapOf[K comparable, V any](with func(*TypedMapTester[K, V])) TypedMapComparer[K, V] {
+	return TypedMapComparer[K, V]{with}
+}
+
+// TypedMapTester is the struct that will be passed to the test function passed
+// to `detest.MapOf`.
+type TypedMapTester[K comparable, V any] struct {
+	mt *MapTester
+}
+
+func (TypedMapComparer[K, V]) accepts(map[K]V) {}
+
+// Compare checks that the value in d.Actual() is a `map[K]V` and then
+// compares it the same way as `MapComparer.Compare`.
+func (mc TypedMapComparer[K, V]) Compare(d *D) {
+	if !checkTyped(d, typeOf[map[K]V](), "detest.MapOf") {
+		return
+	}
+	d.Map(func(mt *MapTester) {
+		mc.with(&TypedMapTester[K, V]{mt})
+	}).Compare(d)
+}
+
+// Key takes a key and an expected value for that key.
+func (tmt *TypedMapTester[K, V]) Key(key K, expect V) {
+	tmt.mt.Key(key, expect)
+}
+
+// KeyPasses takes a key and a comparer for the value of that key.
+func (tmt *TypedMapTester[K, V]) KeyPasses(key K, expect TypedComparer[V]) {
+	tmt.mt.Key(key, expect)
+}
+
+// KeyMatching takes a comparer for keys and an expected value. See
+// `MapTester.KeyMatching`.
+func (tmt *TypedMapTester[K, V]) KeyMatching(key TypedComparer[K], expect V) {
+	tmt.mt.KeyMatching(key, expect)
+}
+
+// NoKey takes a key and checks that it does not exist in the map.
+func (tmt *TypedMapTester[K, V]) NoKey(key K) {
+	tmt.mt.NoKey(key)
+}
+
+// KeysAre takes a list of keys and checks that the map has exactly those keys.
+func (tmt *TypedMapTester[K, V]) KeysAre(keys ...K) {
+	tmt.mt.KeysAre(typedKeys(keys)...)
+}
+
+// KeysInclude takes a list of keys and checks that the map has all of those
+// keys.
+func (tmt *TypedMapTester[K, V]) KeysInclude(keys ...K) {
+	tmt.mt.KeysInclude(typedKeys(keys)...)
+}
+
+func typedKeys[K comparable](keys []K) []interface{} {
+	ks := make([]interface{}, len(keys))
+	for i, k := range keys {
+		ks[i] = k
+	}
+	return ks
+}
+
+// AllValues passes every map value to the given function in turn.
+func (tmt *TypedMapTester[K, V]) AllValues(check func(V) bool) {
+	tmt.mt.AllValues(check)
+}
+
+// Etc means that not all elements of the map will be tested.
+func (tmt *TypedMapTester[K, V]) Etc() {
+	tmt.mt.Etc()
+}
+
+// End means that all elements of the map must be tested or else the test will
+// fail.
+func (tmt *TypedMapTester[K, V]) End() {
+	tmt.mt.End()
+}
+
+// TypedStructComparer implements comparison of struct values of type `T`,
+// which may be a struct or a struct pointer.
+type TypedStructComparer[T any] struct {
+	with func(*TypedStructTester[T])
+}
+
+// StructOf takes a function which will be called to do further comparisons of
+// a struct of type `T`. If `T` is a struct pointer, the pointer is followed
+// whether or not `AutoDeref` is on, and a nil pointer is considered a
+// failure.
+func StructOf[T any](with func(*TypedStructTester[T])) TypedStructComparer[T] {
+	return TypedStructComparer[T]{with}
+}
+
+// TypedStructTester is the struct that will be passed to the test function
+// passed to `detest.StructOf`.
+type TypedStructTester[T any] struct {
+	st *StructTester
+}
+
+func (TypedStructComparer[T]) accepts(T) {}
+
+// Compare checks that the value in d.Actual() is a `T` and then compares it
+// the same way as `StructComparer.Compare`.
+func (sc TypedStructComparer[T]) Compare(d *D) {
+	if !checkTyped(d, typeOf[T](), "detest.StructOf") {
+		return
+	}
+	StructComparer{
+		with: func(st *StructTester) {
+			sc.with(&TypedStructTester[T]{st})
+		},
+		derefPointers: typeOf[T]().Kind() == reflect.Ptr,
+	}.Compare(d)
+}
+
+// Field takes a field name and an expected value for that field. The field
+// names of a struct are not known to the compiler, so this is no different
+// from `StructTester.Field`.
+func (tst *TypedStructTester[T]) Field(field string, expect interface{}) {
+	tst.st.Field(field, expect)
+}
+
+// Method takes a method name and an expected value for each value that the
+// method returns. See `StructTester.Method`.
+func (tst *TypedStructTester[T]) Method(name string, expect ...interface{}) {
+	tst.st.Method(name, expect...)
+}
+
+// MethodWith is like Method, but it takes a slice of arguments to pass to the
+// method. See `StructTester.MethodWith`.
+func (tst *TypedStructTester[T]) MethodWith(name string, args []interface{}, expect ...interface{}) {
+	tst.st.MethodWith(name, args, expect...)
+}
+
+// Etc means that not all fields of the struct will be tested.
+func (tst *TypedStructTester[T]) Etc() {
+	tst.st.Etc()
+}
+
+// End means that all exported fields of the struct must be tested or else
+// the test will fail.
+func (tst *TypedStructTester[T]) End() {
+	tst.st.End()
+}
+
+// EndAll is like End, but unexported fields must be tested as well.
+func (tst *TypedStructTester[T]) EndAll() {
+	tst.st.EndAll()
+}
+
+// TypedFuncComparer implements comparison using a function which takes a
+// `T`.
+type TypedFuncComparer[T any] struct {
+	fc FuncComparer
+}
+
+// FuncOf takes a function and returns a new `TypedFuncComparer` using that
+// function. The function returns a bool indicating success or failure and a
+// string describing the failure. Unlike `d.Func`, this cannot fail, since
+// the compiler checks the function's signature.
+func FuncOf[T any](with func(T) (bool, string)) TypedFuncComparer[T] {
+	return TypedFuncComparer[T]{FuncComparer{reflect.ValueOf(with), "FuncOf()"}}
+}
+
+func (TypedFuncComparer[T]) accepts(T) {}
+
+// Compare calls the function with the value currently in `d.Actual()`.
+func (tfc TypedFuncComparer[T]) Compare(d *D) {
+	tfc.fc.Compare(d)
+}
+
+// TypedAdapter implements a `TypedComparer[T]` using an untyped `Comparer`.
+type TypedAdapter[T any] struct {
+	c Comparer
+}
+
+// Typed takes any `Comparer`, like the ones returned by `d.GT` or
+// `d.HasPrefix`, and returns a `TypedComparer[T]` so that it can be passed to
+// methods like `TypedSliceTester.IdxPasses`. The compiler cannot check that
+// the comparer works with a `T`, so it reports a type mismatch the same way
+// it does when used with the untyped API.
+func Typed[T any](c Comparer) TypedAdapter[T] {
+	return TypedAdapter[T]{c}
+}
+
+func (TypedAdapter[T]) accepts(T) {}
+
+// Compare compares the value in d.Actual() using the wrapped comparer.
+func (ta TypedAdapter[T]) Compare(d *D) {
+	ta.c.Compare(d)
+}
@@ -0,0 +1,370 @@
+package detest
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTyped(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"IsT passes", typedIsTPasses},
+		{"IsT fails", typedIsTFails},
+		{"SliceOf passes", typedSliceOfPasses},
+		{"SliceOf failure matches Slice failure", typedSliceOfFailureMatchesSlice},
+		{"SliceOf AllValues failure matches Slice failure", typedSliceOfAllValuesMatchesSlice},
+		{"SliceOf passed the wrong type", typedSliceOfPassedWrongType},
+		{"MapOf passes", typedMapOfPasses},
+		{"MapOf failure matches Map failure", typedMapOfFailureMatchesMap},
+		{"StructOf failure matches Struct failure", typedStructOfFailureMatchesStruct},
+		{"StructOf with a struct pointer", typedStructOfPointer},
+		{"StructOf with a nil struct pointer", typedStructOfNilPointer},
+		{"FuncOf passes", typedFuncOfPasses},
+		{"FuncOf fails", typedFuncOfFails},
+		{"Typed passes", typedAdapterPasses},
+		{"Typed failure matches untyped failure", typedAdapterFailureMatchesUntyped},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+func typedIsTPasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	IsT(d, []int{1, 2}, []int{1, 2}, "slices are equal")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: slices are equal\n")
+}
+
+func typedIsTFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	IsT(d, []int{1, 2}, []int{1, 3}, "slices are equal")
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		d.state.output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[]int", "[1]"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(t, "detest.(*D).Equal", d.state.output[0].result.path[0].callee, "callee is the untyped method")
+}
+
+func typedSliceOfPasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	PassesT(
+		d,
+		[]int{1, 2},
+		SliceOf(func(st *TypedSliceTester[int]) {
+			st.Idx(0, 1)
+			st.IdxPasses(1, FuncOf(func(v int) (bool, string) { return v == 2, "" }))
+			st.End()
+		}),
+		"slice of ints",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: slice of ints\n")
+}
+
+var callerSuffixRE = regexp.MustCompile(`(@\d+|\.func\d+)$`)
+
+// assertSameOutput checks that the typed and untyped APIs produce the same
+// results, with paths that differ only in which closure or line the caller
+// is.
+func assertSameOutput(t *testing.T, untyped, typed []outputItem) {
+	if !assert.Len(t, typed, len(untyped), "typed API produced the same number of output items") {
+		return
+	}
+	for i := range untyped {
+		u, ty := untyped[i].result, typed[i].result
+		assert.Equal(t, u.pass, ty.pass, "same pass for result %d", i)
+		assert.Equal(t, u.where, ty.where, "same where for result %d", i)
+		assert.Equal(t, u.description, ty.description, "same description for result %d", i)
+		assert.Equal(t, stripCallerSuffixes(u.path), stripCallerSuffixes(ty.path), "same path for result %d", i)
+	}
+}
+
+func stripCallerSuffixes(path []Path) []Path {
+	stripped := []Path{}
+	for _, p := range path {
+		p.caller = callerSuffixRE.ReplaceAllLiteralString(p.caller, "")
+		stripped = append(stripped, p)
+	}
+	return stripped
+}
+
+func typedSliceOfFailureMatchesSlice(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+
+	d.Passes(
+		[]int{1, 2},
+		d.Slice(func(st *SliceTester) {
+			st.Idx(1, 3)
+			st.End()
+		}),
+		"slice of ints",
+	)
+	untyped := d.state.output
+
+	PassesT(
+		d,
+		[]int{1, 2},
+		SliceOf(func(st *TypedSliceTester[int]) {
+			st.Idx(1, 3)
+			st.End()
+		}),
+		"slice of ints",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assertSameOutput(t, untyped, d.state.output)
+}
+
+func typedSliceOfAllValuesMatchesSlice(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+
+	d.Passes(
+		[]int{1, 2},
+		d.Slice(func(st *SliceTester) {
+			st.AllValues(func(v int) bool { return v < 2 })
+			st.End()
+		}),
+		"slice of ints",
+	)
+	untyped := d.state.output
+
+	PassesT(
+		d,
+		[]int{1, 2},
+		SliceOf(func(st *TypedSliceTester[int]) {
+			st.AllValues(func(v int) bool { return v < 2 })
+			st.End()
+		}),
+		"slice of ints",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assertSameOutput(t, untyped, d.state.output)
+}
+
+func typedSliceOfPassedWrongType(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(
+		[]string{"a"},
+		SliceOf(func(st *TypedSliceTester[int]) {
+			st.Idx(0, 1)
+		}),
+		"slice of ints",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		d.state.output,
+		[]resultExpect{
+			{
+				pass:     false,
+				dataPath: []string{"[]string"},
+			},
+		},
+		"got expected results",
+	)
+	assert.Equal(
+		t,
+		"Called detest.SliceOf() but the value being tested isn't a []int, it's a []string",
+		d.state.output[0].result.description,
+		"got expected description",
+	)
+}
+
+func typedMapOfPasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	PassesT(
+		d,
+		map[string]int{"a": 1, "b": 2},
+		MapOf(func(mt *TypedMapTester[string, int]) {
+			mt.Key("a", 1)
+			mt.AllValues(func(v int) bool { return v > 0 })
+			mt.End()
+		}),
+		"map of ints",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: map of ints\n")
+}
+
+func typedMapOfFailureMatchesMap(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+
+	d.Passes(
+		map[string]int{"a": 1, "b": 2},
+		d.Map(func(mt *MapTester) {
+			mt.Key("a", 2)
+			mt.End()
+		}),
+		"map of ints",
+	)
+	untyped := d.state.output
+
+	PassesT(
+		d,
+		map[string]int{"a": 1, "b": 2},
+		MapOf(func(mt *TypedMapTester[string, int]) {
+			mt.Key("a", 2)
+			mt.End()
+		}),
+		"map of ints",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assertSameOutput(t, untyped, d.state.output)
+}
+
+func typedStructOfFailureMatchesStruct(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+
+	d.Passes(
+		order{ID: 1},
+		d.Struct(func(st *StructTester) {
+			st.Field("ID", 2)
+			st.Etc()
+		}),
+		"order",
+	)
+	untyped := d.state.output
+
+	PassesT(
+		d,
+		order{ID: 1},
+		StructOf(func(st *TypedStructTester[order]) {
+			st.Field("ID", 2)
+			st.Etc()
+		}),
+		"order",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assertSameOutput(t, untyped, d.state.output)
+}
+
+func typedStructOfPointer(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(
+		&order{ID: 1},
+		StructOf(func(st *TypedStructTester[*order]) {
+			st.Field("ID", 1)
+			st.Field("Items", 2)
+			st.Etc()
+		}),
+		"order pointer",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"*order", "*", ".ID", "int"}},
+			{pass: false, dataPath: []string{"*order", "*", ".Items", "map[string]int"}},
+		},
+		"got expected results",
+	)
+}
+
+func typedStructOfNilPointer(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(
+		(*order)(nil),
+		StructOf(func(st *TypedStructTester[*order]) {
+			st.Field("ID", 1)
+			st.Etc()
+		}),
+		"nil order pointer",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "one output item")
+	assert.Equal(
+		t,
+		"Called detest.Struct() but the pointer being tested is nil",
+		r.record[0].output[0].result.description,
+		"got expected description",
+	)
+}
+
+func typedFuncOfPasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	PassesT(d, 42, FuncOf(func(v int) (bool, string) { return v > 40, "" }), "big number")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: big number\n")
+}
+
+func typedFuncOfFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	PassesT(d, 4, FuncOf(func(v int) (bool, string) { return v > 40, "too small" }), "big number")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, d.state.output, 1, "one output item")
+	assert.Equal(t, "too small", d.state.output[0].result.description, "got expected description")
+}
+
+func typedAdapterPasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	PassesT(d, 3, Typed[int](d.GT(1)), "int")
+	PassesT(
+		d,
+		map[string]int{"apple": 1, "banana": 2},
+		MapOf(func(mt *TypedMapTester[string, int]) {
+			mt.KeyMatching(Typed[string](d.HasPrefix("b")), 2)
+			mt.KeyPasses("apple", Typed[int](d.Approx(1, 0)))
+			mt.End()
+		}),
+		"map of ints",
+	)
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: int\n")
+}
+
+func typedAdapterFailureMatchesUntyped(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+
+	d.Passes(
+		[]int{1, 2},
+		d.Slice(func(st *SliceTester) {
+			st.Idx(1, d.GT(5))
+			st.Etc()
+		}),
+		"slice of ints",
+	)
+	untyped := d.state.output
+
+	PassesT(
+		d,
+		[]int{1, 2},
+		SliceOf(func(st *TypedSliceTester[int]) {
+			st.IdxPasses(1, Typed[int](d.GT(5)))
+			st.Etc()
+		}),
+		"slice of ints",
+	)
+	mockT.AssertCalled(t, "Fail")
+	assertSameOutput(t, untyped, d.state.output)
+}
@@ -0,0 +1,150 @@
+package detest
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypeComparer implements checks of the dynamic type of a value. It is
+// returned by `IsType`, `Implements`, and `Kind`.
+type TypeComparer struct {
+	called string
+	op     string
+	// The expected value to show in the output.
+	expect *value
+	// If this is not empty then the comparer was used incorrectly and this
+	// describes the problem.
+	usage string
+	check func(reflect.Type) bool
+	then  []Comparer
+}
+
+// IsType takes an example value and returns a TypeComparer which passes if
+// the actual value has exactly the same type as the example. Any comparers
+// passed after the example are only run if the type matches, so you can write
+// something like `d.IsType(&Foo{}, d.Struct(...))`.
+func (d *D) IsType(example interface{}, then ...Comparer) TypeComparer {
+	tc := TypeComparer{
+		called: "IsType",
+		op:     "is type",
+		then:   then,
+	}
+
+	ty := reflect.TypeOf(example)
+	if ty == nil {
+		tc.usage = "You passed nil to IsType() but it needs a value of the type you expect"
+		return tc
+	}
+
+	tc.expect = &value{value: example, desc: describeType(ty)}
+	tc.check = func(actual reflect.Type) bool {
+		return actual == ty
+	}
+	return tc
+}
+
+// Implements takes a nil pointer to an interface, like `(*io.Reader)(nil)`,
+// and returns a TypeComparer which passes if the actual value implements that
+// interface. Any comparers passed after the interface are only run if the
+// value implements it.
+func (d *D) Implements(iface interface{}, then ...Comparer) TypeComparer {
+	tc := TypeComparer{
+		called: "Implements",
+		op:     "implements",
+		then:   then,
+	}
+
+	ty := reflect.TypeOf(iface)
+	if ty == nil || ty.Kind() != reflect.Ptr || ty.Elem().Kind() != reflect.Interface {
+		tc.usage = fmt.Sprintf(
+			"You passed %s to Implements() but it needs a nil pointer to an interface, like (*io.Reader)(nil)",
+			articleize(describeTypeOfActualValue(iface)),
+		)
+		return tc
+	}
+
+	ty = ty.Elem()
+	tc.expect = &value{value: ty.String(), desc: describeType(ty)}
+	tc.check = func(actual reflect.Type) bool {
+		return actual != nil && actual.Implements(ty)
+	}
+	return tc
+}
+
+// Kind takes a `reflect.Kind` and returns a TypeComparer which passes if the
+// actual value is of that kind. Any comparers passed after the kind are only
+// run if the kind matches.
+func (d *D) Kind(kind reflect.Kind, then ...Comparer) TypeComparer {
+	return TypeComparer{
+		called: "Kind",
+		op:     "kind",
+		expect: &value{value: kind, desc: kind.String()},
+		check: func(actual reflect.Type) bool {
+			return actual != nil && actual.Kind() == kind
+		},
+		then: then,
+	}
+}
+
+// Compare checks the type of the value in d.Actual(). If the check passes, it
+// runs any comparers that were passed along with the expected type.
+func (tc TypeComparer) Compare(d *D) {
+	actual := d.Actual()
+	actualType := reflect.TypeOf(actual)
+	d.PushPath(d.NewPath(describeType(actualType), 1, "detest.(*D)."+tc.called))
+	defer d.PopPath()
+
+	if tc.usage != "" {
+		d.AddResult(result{
+			actual:      newValue(actual),
+			pass:        false,
+			where:       inUsage,
+			description: tc.usage,
+		})
+		return
+	}
+
+	result := result{
+		actual: newValue(actual),
+		expect: tc.expect,
+		op:     tc.op,
+		pass:   tc.check(actualType),
+	}
+	if !result.pass {
+		result.where = inType
+		result.description = tc.failureDescription(actualType)
+	}
+	d.AddResult(result)
+	if !result.pass {
+		return
+	}
+
+	for _, c := range tc.then {
+		c.Compare(d)
+	}
+}
+
+func (tc TypeComparer) failureDescription(actual reflect.Type) string {
+	desc := articleize(describeType(actual))
+
+	switch tc.called {
+	case "Implements":
+		return fmt.Sprintf(
+			"Called detest.Implements() but the value being tested is %s, which does not implement %s",
+			desc, tc.expect.value,
+		)
+	case "Kind":
+		kind := "nil"
+		if actual != nil {
+			kind = actual.Kind().String()
+		}
+		return fmt.Sprintf(
+			"Called detest.Kind() but the value being tested is %s, which has the kind %s, not %s",
+			desc, kind, tc.expect.value,
+		)
+	}
+	return fmt.Sprintf(
+		"Called detest.IsType() but the value being tested is %s, not %s",
+		desc, articleize(tc.expect.desc),
+	)
+}
@@ -0,0 +1,186 @@
+package detest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typesTestStruct struct {
+	Name string
+}
+
+func TestTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T)
+	}{
+		{"IsType passes", typesIsTypePasses},
+		{"IsType fails", typesIsTypeFails},
+		{"Implements passes", typesImplementsPasses},
+		{"Implements fails", typesImplementsFails},
+		{"Kind", typesKind},
+		{"Usage errors", typesUsageErrors},
+		{"Chained comparers", typesChained},
+		{"Chained comparers are skipped on failure", typesChainedSkipped},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.fn)
+	}
+}
+
+func typesIsTypePasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(typesTestStruct{}, d.IsType(typesTestStruct{}), "struct")
+	d.Passes(&typesTestStruct{}, d.IsType(&typesTestStruct{}), "pointer")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: struct\n")
+}
+
+func typesIsTypeFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(typesTestStruct{}, r.IsType(&typesTestStruct{}), "struct and pointer")
+	r.Passes(nil, r.IsType(42), "nil")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 2, "two states were recorded")
+
+	res := r.record[0].output[0].result
+	assert.Equal(t, inType, res.where, "failure is in the type")
+	assert.Equal(t, "detest.(*D).IsType", res.path[0].callee, "callee is IsType")
+	assert.Equal(
+		t,
+		"Called detest.IsType() but the value being tested is a typesTestStruct, not a *typesTestStruct",
+		res.description,
+		"got expected description",
+	)
+
+	assert.Equal(
+		t,
+		"Called detest.IsType() but the value being tested is a nil, not an int",
+		r.record[1].output[0].result.description,
+		"got expected description for nil",
+	)
+}
+
+func typesImplementsPasses(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	d.Passes(&bytes.Buffer{}, d.Implements((*io.Reader)(nil)), "io.Reader")
+	d.Passes(fmt.Errorf("x"), d.Implements((*error)(nil)), "error")
+	mockT.AssertNotCalled(t, "Fail")
+	mockT.AssertCalled(t, "WriteString", "Assertion ok: io.Reader\n")
+}
+
+func typesImplementsFails(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(42, r.Implements((*io.Reader)(nil)), "int")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record[0].output, 1, "record has state with one output item")
+	res := r.record[0].output[0].result
+	assert.Equal(t, inType, res.where, "failure is in the type")
+	assert.Equal(t, "detest.(*D).Implements", res.path[0].callee, "callee is Implements")
+	assert.Equal(
+		t,
+		"Called detest.Implements() but the value being tested is an int, which does not implement io.Reader",
+		res.description,
+		"got expected description",
+	)
+}
+
+func typesKind(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(map[string]int{}, r.Kind(reflect.Map), "map")
+	r.Passes([]int{}, r.Kind(reflect.Map), "slice")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 2, "two states were recorded")
+	assert.True(t, r.record[0].output[0].result.pass, "a map has the map kind")
+
+	res := r.record[1].output[0].result
+	assert.Equal(t, inType, res.where, "failure is in the type")
+	assert.Equal(t, "detest.(*D).Kind", res.path[0].callee, "callee is Kind")
+	assert.Equal(
+		t,
+		"Called detest.Kind() but the value being tested is a []int, which has the kind slice, not map",
+		res.description,
+		"got expected description",
+	)
+}
+
+func typesUsageErrors(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(42, r.IsType(nil), "IsType with nil")
+	r.Passes(42, r.Implements(&bytes.Buffer{}), "Implements with non-interface")
+	mockT.AssertCalled(t, "Fail")
+	assert.Len(t, r.record, 2, "two states were recorded")
+	assert.Equal(t, inUsage, r.record[0].output[0].result.where, "failure is a usage error")
+	assert.Equal(
+		t,
+		"You passed nil to IsType() but it needs a value of the type you expect",
+		r.record[0].output[0].result.description,
+		"got expected description for IsType",
+	)
+	assert.Equal(t, inUsage, r.record[1].output[0].result.where, "failure is a usage error")
+	assert.Equal(
+		t,
+		"You passed a *Buffer to Implements() but it needs a nil pointer to an interface, like (*io.Reader)(nil)",
+		r.record[1].output[0].result.description,
+		"got expected description for Implements",
+	)
+}
+
+func typesChained(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	var actual interface{} = &typesTestStruct{Name: "x"}
+	r.Passes(
+		actual,
+		r.IsType(&typesTestStruct{}, r.Deref(r.Struct(func(st *StructTester) {
+			st.Field("Name", "y")
+			st.End()
+		}))),
+		"chained",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{
+			{pass: true, dataPath: []string{"*typesTestStruct"}},
+			{pass: false, dataPath: []string{"*typesTestStruct", "*", "typesTestStruct", ".Name", "string"}},
+		},
+		"got expected results",
+	)
+}
+
+func typesChainedSkipped(t *testing.T) {
+	mockT := new(mockT)
+	d := NewWithOutput(mockT, mockT)
+	r := NewRecorder(d)
+	r.Passes(
+		42,
+		r.IsType("", r.HasPrefix("x")),
+		"chained",
+	)
+	mockT.AssertCalled(t, "Fail")
+	AssertResultsAre(
+		t,
+		r.record[0].output,
+		[]resultExpect{{pass: false, dataPath: []string{"int"}}},
+		"got expected results",
+	)
+}